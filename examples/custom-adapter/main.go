@@ -12,23 +12,19 @@ type MyCustomLogger struct {
 }
 
 func (m MyCustomLogger) Info(msg string, ctx map[string]interface{}) {
-	fmt.Printf("%s [INFO] %s | Context: %v
-", m.Prefix, msg, ctx)
+	fmt.Printf("%s [INFO] %s | Context: %v\n", m.Prefix, msg, ctx)
 }
 
 func (m MyCustomLogger) Warn(msg string, ctx map[string]interface{}) {
-	fmt.Printf("%s [WARN] %s
-", m.Prefix, msg)
+	fmt.Printf("%s [WARN] %s\n", m.Prefix, msg)
 }
 
 func (m MyCustomLogger) Error(msg string, ctx map[string]interface{}) {
-	fmt.Printf("%s [ERROR] %s
-", m.Prefix, msg)
+	fmt.Printf("%s [ERROR] %s\n", m.Prefix, msg)
 }
 
 func (m MyCustomLogger) Fatal(msg string, ctx map[string]interface{}) {
-	fmt.Printf("%s [FATAL] %s
-", m.Prefix, msg)
+	fmt.Printf("%s [FATAL] %s\n", m.Prefix, msg)
 }
 
 // 2. Define your setting that implements ports.LoggerSetting interface
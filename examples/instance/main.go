@@ -0,0 +1,33 @@
+package main
+
+import (
+	"github.com/Consolushka/golang.composite_logger/pkg"
+	"github.com/Consolushka/golang.composite_logger/pkg/adapters/setting"
+)
+
+func main() {
+	// Each subsystem gets its own logger with its own set of adapters
+	audit, err := composite_logger.New(
+		setting.FileSetting{
+			Enabled:    true,
+			Path:       "logs/audit.log",
+			LowerLevel: composite_logger.InfoLevel,
+		},
+	)
+	if err != nil {
+		panic(err)
+	}
+	defer audit.Stop()
+
+	// The package-level functions keep working through the global instance
+	composite_logger.Init(
+		setting.ConsoleSetting{
+			Enabled:    true,
+			LowerLevel: composite_logger.InfoLevel,
+		},
+	)
+	defer composite_logger.Stop()
+
+	audit.Info("User role changed", map[string]interface{}{"user_id": 42, "role": "admin"})
+	composite_logger.Info("Request served", map[string]interface{}{"path": "/users/42"})
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"log/slog"
+
+	"github.com/Consolushka/golang.composite_logger/pkg"
+	"github.com/Consolushka/golang.composite_logger/pkg/adapters/setting"
+)
+
+func main() {
+	composite_logger.Init(
+		setting.ConsoleSetting{
+			Enabled:    true,
+			LowerLevel: composite_logger.DebugLevel,
+		},
+	)
+	defer composite_logger.Stop()
+
+	// Route log/slog through the composite logger
+	slog.SetDefault(slog.New(composite_logger.NewSlogHandler(nil, &composite_logger.SlogHandlerOptions{
+		Level: composite_logger.DebugLevel,
+	})))
+
+	slog.Debug("Cache warmed up", "entries", 1024)
+
+	// Groups are flattened into dotted keys: "http.method", "http.status"
+	slog.With("service", "api").WithGroup("http").Info("Request served", "method", "GET", "status", 200)
+}
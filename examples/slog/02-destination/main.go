@@ -0,0 +1,26 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+
+	"github.com/Consolushka/golang.composite_logger/pkg"
+	"github.com/Consolushka/golang.composite_logger/pkg/adapters/setting"
+)
+
+func main() {
+	// Any slog.Handler can be used as a destination
+	composite_logger.Init(
+		setting.SlogSetting{
+			Enabled:    true,
+			Handler:    slog.NewJSONHandler(os.Stderr, nil),
+			LowerLevel: composite_logger.InfoLevel,
+		},
+	)
+	defer composite_logger.Stop()
+
+	// Nested maps become slog groups: {"user":{"id":42,"plan":"pro"}}
+	composite_logger.Info("Subscription renewed", map[string]interface{}{
+		"user": map[string]interface{}{"id": 42, "plan": "pro"},
+	})
+}
@@ -1,17 +1,29 @@
 package logger
 
-import "github.com/sirupsen/logrus"
+import (
+	composite_logger "github.com/Consolushka/golang.composite_logger/pkg"
+	"github.com/sirupsen/logrus"
+)
 
 type ConsoleLogger struct {
 	logrus *logrus.Logger
 }
 
 func NewConsoleLogger(logger *logrus.Logger) ConsoleLogger {
+	checkWrites(logger)
 	return ConsoleLogger{
 		logrus: logger,
 	}
 }
 
+func (c ConsoleLogger) Trace(message string, context map[string]interface{}) {
+	c.logrus.WithFields(context).Trace(message)
+}
+
+func (c ConsoleLogger) Debug(message string, context map[string]interface{}) {
+	c.logrus.WithFields(context).Debug(message)
+}
+
 func (c ConsoleLogger) Info(message string, context map[string]interface{}) {
 	c.logrus.WithFields(context).Info(message)
 }
@@ -27,3 +39,13 @@ func (c ConsoleLogger) Error(message string, context map[string]interface{}) {
 func (c ConsoleLogger) Fatal(message string, context map[string]interface{}) {
 	c.logrus.WithFields(context).Log(logrus.FatalLevel, message)
 }
+
+// TryLog writes the entry with the timestamp of the original log call and returns the write error.
+func (c ConsoleLogger) TryLog(entry composite_logger.Entry) error {
+	return writeLogrus(c.logrus, entry)
+}
+
+// Log writes the entry with the timestamp of the original log call.
+func (c ConsoleLogger) Log(entry composite_logger.Entry) {
+	c.logrus.WithTime(entry.Time).WithFields(entry.Fields).Log(entry.Level.ToLogrus(), entry.Message)
+}
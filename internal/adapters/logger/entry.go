@@ -0,0 +1,17 @@
+package logger
+
+import (
+	"time"
+
+	composite_logger "github.com/Consolushka/golang.composite_logger/pkg"
+)
+
+// newEntry builds an entry for adapters called directly through their per-level methods.
+func newEntry(level composite_logger.Level, message string, context map[string]interface{}) composite_logger.Entry {
+	return composite_logger.Entry{
+		Level:   level,
+		Time:    time.Now(),
+		Message: message,
+		Fields:  context,
+	}
+}
@@ -1,13 +1,30 @@
 package logger
 
-import "github.com/sirupsen/logrus"
+import (
+	"context"
+	"io"
+
+	composite_logger "github.com/Consolushka/golang.composite_logger/pkg"
+	"github.com/sirupsen/logrus"
+)
 
 type FileLogger struct {
 	logrus *logrus.Logger
+	file   io.Closer
+}
+
+// NewFileLogger creates a file logger; file is the rotating log file closed on shutdown and may be nil.
+func NewFileLogger(logrusInstance *logrus.Logger, file io.Closer) FileLogger {
+	checkWrites(logrusInstance)
+	return FileLogger{logrus: logrusInstance, file: file}
 }
 
-func NewFileLogger(logrusInstance *logrus.Logger) FileLogger {
-	return FileLogger{logrusInstance}
+func (f FileLogger) Trace(message string, context map[string]interface{}) {
+	f.logrus.WithFields(context).Trace(message)
+}
+
+func (f FileLogger) Debug(message string, context map[string]interface{}) {
+	f.logrus.WithFields(context).Debug(message)
 }
 
 func (f FileLogger) Info(message string, context map[string]interface{}) {
@@ -25,3 +42,22 @@ func (f FileLogger) Error(message string, context map[string]interface{}) {
 func (f FileLogger) Fatal(message string, context map[string]interface{}) {
 	f.logrus.WithFields(context).Log(logrus.FatalLevel, message)
 }
+
+// TryLog writes the entry with the timestamp of the original log call and returns the write error.
+func (f FileLogger) TryLog(entry composite_logger.Entry) error {
+	return writeLogrus(f.logrus, entry)
+}
+
+// Log writes the entry with the timestamp of the original log call.
+func (f FileLogger) Log(entry composite_logger.Entry) {
+	f.logrus.WithTime(entry.Time).WithFields(entry.Fields).Log(entry.Level.ToLogrus(), entry.Message)
+}
+
+// Close closes the log file. Later writes reopen it.
+func (f FileLogger) Close(_ context.Context) error {
+	if f.file == nil {
+		return nil
+	}
+
+	return f.file.Close()
+}
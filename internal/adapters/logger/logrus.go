@@ -0,0 +1,66 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	composite_logger "github.com/Consolushka/golang.composite_logger/pkg"
+	"github.com/sirupsen/logrus"
+)
+
+// writeErrorKey is the context key under which writeLogrus passes the slot for the write error to writeChecker.
+type writeErrorKey struct{}
+
+// writeChecker is installed as both the formatter and the output of a logrus logger, so the result of a write
+// reaches the caller instead of only being printed to standard error. logrus formats and writes an entry while
+// holding its own lock, so the entry being written is always the last one formatted.
+type writeChecker struct {
+	formatter logrus.Formatter
+	out       io.Writer
+	// current is the error slot of the entry being written, nil for entries logged without one.
+	current *error
+}
+
+// checkWrites installs a writeChecker on the logger. It must be called before the logger is used.
+func checkWrites(l *logrus.Logger) {
+	checker := &writeChecker{formatter: l.Formatter, out: l.Out}
+	l.SetFormatter(checker)
+	l.SetOutput(checker)
+}
+
+// Format formats the entry with the wrapped formatter and remembers where to store its write error.
+func (w *writeChecker) Format(e *logrus.Entry) ([]byte, error) {
+	w.current = nil
+	if e.Context != nil {
+		w.current, _ = e.Context.Value(writeErrorKey{}).(*error)
+	}
+
+	serialized, err := w.formatter.Format(e)
+	if err != nil && w.current != nil {
+		*w.current = fmt.Errorf("failed to format entry: %w", err)
+	}
+
+	return serialized, err
+}
+
+// Write writes to the wrapped output and stores a failure in the error slot of the entry being written.
+func (w *writeChecker) Write(p []byte) (int, error) {
+	n, err := w.out.Write(p)
+	if err != nil && w.current != nil {
+		*w.current = fmt.Errorf("failed to write entry: %w", err)
+	}
+
+	return n, err
+}
+
+// writeLogrus logs the entry through logrus, under its lock, and returns the format or write error,
+// which Entry.Log only prints to standard error. Hook errors are still only printed.
+// The logger must have been prepared with checkWrites; otherwise no error is returned.
+func writeLogrus(l *logrus.Logger, entry composite_logger.Entry) error {
+	var err error
+	ctx := context.WithValue(context.Background(), writeErrorKey{}, &err)
+	l.WithContext(ctx).WithTime(entry.Time).WithFields(entry.Fields).Log(entry.Level.ToLogrus(), entry.Message)
+
+	return err
+}
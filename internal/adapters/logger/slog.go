@@ -0,0 +1,159 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"time"
+
+	composite_logger "github.com/Consolushka/golang.composite_logger/pkg"
+)
+
+type SlogLogger struct {
+	handler slog.Handler
+	level   composite_logger.Level
+}
+
+func NewSlogLogger(handler slog.Handler, level composite_logger.Level) SlogLogger {
+	return SlogLogger{
+		handler: handler,
+		level:   level,
+	}
+}
+
+func (s SlogLogger) Trace(message string, context map[string]interface{}) {
+	s.Log(newEntry(composite_logger.TraceLevel, message, context))
+}
+
+func (s SlogLogger) Debug(message string, context map[string]interface{}) {
+	s.Log(newEntry(composite_logger.DebugLevel, message, context))
+}
+
+func (s SlogLogger) Info(message string, context map[string]interface{}) {
+	s.Log(newEntry(composite_logger.InfoLevel, message, context))
+}
+
+func (s SlogLogger) Warn(message string, context map[string]interface{}) {
+	s.Log(newEntry(composite_logger.WarningLevel, message, context))
+}
+
+func (s SlogLogger) Error(message string, context map[string]interface{}) {
+	s.Log(newEntry(composite_logger.ErrorLevel, message, context))
+}
+
+func (s SlogLogger) Fatal(message string, context map[string]interface{}) {
+	s.Log(newEntry(composite_logger.FatalLevel, message, context))
+}
+
+// Log converts the entry into a slog.Record with typed attributes and passes it to the handler.
+func (s SlogLogger) Log(entry composite_logger.Entry) {
+	_ = s.TryLog(entry)
+}
+
+// TryLog passes the entry to the handler and returns the handler error.
+func (s SlogLogger) TryLog(entry composite_logger.Entry) error {
+	if s.level > entry.Level {
+		return nil
+	}
+
+	ctx := context.Background()
+	level := entry.Level.ToSlog()
+	if !s.handler.Enabled(ctx, level) {
+		return nil
+	}
+
+	record := slog.NewRecord(entry.Time, level, entry.Message, 0)
+	record.AddAttrs(slogAttrs(entry.Fields)...)
+
+	return s.handler.Handle(ctx, record)
+}
+
+// slogAttrs converts a context map into attributes sorted by key.
+// Nested maps become groups, errors are kept as error values so handlers can render them.
+func slogAttrs(context map[string]interface{}) []slog.Attr {
+	keys := make([]string, 0, len(context))
+	for key := range context {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	attrs := make([]slog.Attr, 0, len(keys))
+	for _, key := range keys {
+		attrs = append(attrs, slogAttr(key, context[key]))
+	}
+
+	return attrs
+}
+
+func slogAttr(key string, value interface{}) slog.Attr {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		return slog.Attr{Key: key, Value: slog.GroupValue(slogAttrs(typed)...)}
+	case map[string]string:
+		nested := make(map[string]interface{}, len(typed))
+		for k, v := range typed {
+			nested[k] = v
+		}
+		return slog.Attr{Key: key, Value: slog.GroupValue(slogAttrs(nested)...)}
+	default:
+		return slog.Any(key, value)
+	}
+}
+
+// LogAttrs passes an entry with typed fields to the handler without building a context map.
+// Bound fields come first, sorted by key, followed by the typed fields in call order.
+func (s SlogLogger) LogAttrs(entry composite_logger.Entry) error {
+	if s.level > entry.Level {
+		return nil
+	}
+
+	ctx := context.Background()
+	level := entry.Level.ToSlog()
+	if !s.handler.Enabled(ctx, level) {
+		return nil
+	}
+
+	record := slog.NewRecord(entry.Time, level, entry.Message, 0)
+	for _, attr := range slogAttrs(entry.Fields) {
+		if !hasAttr(entry.Attrs, attr.Key) {
+			record.AddAttrs(attr)
+		}
+	}
+	for _, field := range entry.Attrs {
+		record.AddAttrs(slogField(field))
+	}
+	if entry.Logger != "" {
+		record.AddAttrs(slog.String(composite_logger.LoggerKey, entry.Logger))
+	}
+	if entry.Stack != "" {
+		record.AddAttrs(slog.String("stackTrace", entry.Stack))
+	}
+
+	return s.handler.Handle(ctx, record)
+}
+
+// slogField converts a typed field into an attribute without boxing scalar values.
+func slogField(field composite_logger.Field) slog.Attr {
+	switch field.Type {
+	case composite_logger.StringType:
+		return slog.String(field.Key, field.String)
+	case composite_logger.IntType, composite_logger.Int64Type:
+		return slog.Int64(field.Key, field.Integer)
+	case composite_logger.BoolType:
+		return slog.Bool(field.Key, field.Integer == 1)
+	case composite_logger.DurationType:
+		return slog.Duration(field.Key, time.Duration(field.Integer))
+	default:
+		return slogAttr(field.Key, field.Value())
+	}
+}
+
+func hasAttr(fields []composite_logger.Field, key string) bool {
+	for _, field := range fields {
+		if field.Key == key {
+			return true
+		}
+	}
+
+	return false
+}
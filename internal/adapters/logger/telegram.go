@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"regexp"
@@ -8,6 +9,7 @@ import (
 	"time"
 
 	composite_logger "github.com/Consolushka/golang.composite_logger/pkg"
+	"github.com/Consolushka/golang.composite_logger/pkg/ports"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
@@ -19,54 +21,148 @@ type TelegramLogger struct {
 	UseLevelTitleWrapper bool
 	LevelWrappers        map[composite_logger.Level]string
 	LevelTitles          map[composite_logger.Level]string
+	// ErrorHandler receives failed sends. When nil, failures are printed to standard output.
+	ErrorHandler func(entry composite_logger.Entry, err error)
+	// Sender queues messages and paces them to the rate Telegram allows. When nil, messages are sent by the caller.
+	Sender *TelegramSender
+	// Pacer paces messages sent by the caller and waits for retry_after, for at most MaxWait per message.
+	// When nil, messages are sent at once.
+	Pacer *TelegramPacer
+	// MaxWait bounds how long the caller waits for the Pacer. A message that would wait longer is not sent
+	// and ErrTelegramThrottled is returned.
+	MaxWait time.Duration
+	// NoFallback skips the plain text message sent when Telegram rejects the formatted one.
+	// It is set when the logger is wrapped in RetrySetting, so a retried message is never sent twice.
+	NoFallback bool
+}
+
+// WithRetries returns a copy of the logger without the plain text fallback, for RetrySetting.
+func (t TelegramLogger) WithRetries() ports.Logger {
+	t.NoFallback = true
+	return t
+}
+
+// WithErrorHandler returns a copy of the logger that reports failed sends to handler.
+func (t TelegramLogger) WithErrorHandler(handler func(entry composite_logger.Entry, err error)) ports.Logger {
+	t.ErrorHandler = handler
+	return t
+}
+
+func (t TelegramLogger) Trace(message string, context map[string]interface{}) {
+	t.Log(newEntry(composite_logger.TraceLevel, message, context))
+}
+
+func (t TelegramLogger) Debug(message string, context map[string]interface{}) {
+	t.Log(newEntry(composite_logger.DebugLevel, message, context))
 }
 
 func (t TelegramLogger) Info(message string, context map[string]interface{}) {
-	t.send(message, context, composite_logger.InfoLevel)
+	t.Log(newEntry(composite_logger.InfoLevel, message, context))
 }
 
 func (t TelegramLogger) Warn(message string, context map[string]interface{}) {
-	t.send(message, context, composite_logger.WarningLevel)
+	t.Log(newEntry(composite_logger.WarningLevel, message, context))
 }
 
 func (t TelegramLogger) Error(message string, context map[string]interface{}) {
-	t.send(message, context, composite_logger.ErrorLevel)
+	t.Log(newEntry(composite_logger.ErrorLevel, message, context))
 }
 
 func (t TelegramLogger) Fatal(message string, context map[string]interface{}) {
-	t.send(message, context, composite_logger.FatalLevel)
+	t.Log(newEntry(composite_logger.FatalLevel, message, context))
 }
 
-func (t TelegramLogger) send(message string, context map[string]interface{}, level composite_logger.Level) {
-	if t.Level > level {
-		return
+// Log sends the entry, stamped with the time of the original log call.
+// Failed sends are passed to ErrorHandler.
+func (t TelegramLogger) Log(entry composite_logger.Entry) {
+	if err := t.TryLog(entry); err != nil {
+		t.reportError(entry, err)
+	}
+}
+
+// TryLog sends the entry and returns the send error, a *TelegramError. If the formatted message is rejected,
+// a plain text fallback is sent unless NoFallback is set, and the error is still returned.
+// With a Sender the entry is only queued: TryLog fails if the queue is full, and send failures go to ErrorHandler.
+func (t TelegramLogger) TryLog(entry composite_logger.Entry) error {
+	if t.Level > entry.Level {
+		return nil
 	}
 
-	text := formatTelegramMarkdown(message, context, level, t)
+	text := formatTelegramMarkdown(entry, t)
+	if t.Sender != nil {
+		return t.Sender.enqueue(telegramMessage{entry: entry, text: text, report: t.reportError})
+	}
+
+	return t.send(text, entry.Message)
+}
 
-	tgMessage := tgbotapi.NewMessage(t.LogChatId, text)
-	tgMessage.ParseMode = "MarkdownV2"
+// send sends the message from the calling goroutine. With a Pacer it waits for its turn and, when Telegram
+// answers 429 Too Many Requests, as long as Telegram asks and sends again, as long as MaxWait allows.
+func (t TelegramLogger) send(text string, plain string) error {
+	if t.Pacer == nil {
+		return sendTelegram(t.BotApi, t.LogChatId, text, plain, !t.NoFallback)
+	}
 
-	if _, err := t.BotApi.Send(tgMessage); err != nil {
-		fmt.Printf("[TelegramLogger Error] Failed to send detailed log to ChatID %d: %v\n", t.LogChatId, err)
+	deadline := time.Now().Add(t.MaxWait)
+	var err error
+	for attempt := 1; attempt <= maxThrottledAttempts; attempt++ {
+		now := time.Now()
+		wait, ok := t.Pacer.reserve(now, max(deadline.Sub(now), 0))
+		if !ok {
+			if err != nil {
+				return err
+			}
+			return &TelegramError{ChatID: t.LogChatId, Err: ErrTelegramThrottled, wait: wait}
+		}
+		time.Sleep(wait)
 
-		// Fallback: send simple plain text message without Markdown
-		fallbackText := fmt.Sprintf("⚠️ [TelegramLogger Error]\nFailed to send detailed log.\nError: %v\nMessage: %s", err, message)
-		fallbackMsg := tgbotapi.NewMessage(t.LogChatId, fallbackText)
-		if _, fallbackErr := t.BotApi.Send(fallbackMsg); fallbackErr != nil {
-			fmt.Printf("[TelegramLogger Error] Failed to send fallback message to ChatID %d: %v\n", t.LogChatId, fallbackErr)
+		err = sendTelegram(t.BotApi, t.LogChatId, text, plain, !t.NoFallback)
+		wait, throttled := retryAfter(err)
+		if !throttled {
+			return err
 		}
+		t.Pacer.throttle(time.Now(), wait)
 	}
+
+	return err
+}
+
+// Flush waits until the messages queued by the Sender have been sent.
+func (t TelegramLogger) Flush(ctx context.Context) error {
+	if t.Sender == nil {
+		return nil
+	}
+
+	return t.Sender.Flush(ctx)
+}
+
+// Close sends the messages queued by the Sender and stops it.
+func (t TelegramLogger) Close(ctx context.Context) error {
+	if t.Sender == nil {
+		return nil
+	}
+
+	return t.Sender.Close(ctx)
+}
+
+func (t TelegramLogger) reportError(entry composite_logger.Entry, err error) {
+	if t.ErrorHandler == nil {
+		fmt.Printf("[TelegramLogger Error] %v\n", err)
+		return
+	}
+
+	t.ErrorHandler(entry, err)
 }
 
-func formatTelegramMarkdown(message string, context map[string]interface{}, level composite_logger.Level, t TelegramLogger) string {
+func formatTelegramMarkdown(entry composite_logger.Entry, t TelegramLogger) string {
 	escapeMarkdownV2 := func(text string) string {
 		var markdownV2Regex = regexp.MustCompile(`([\[\]\-_*~` + "`" + `>#+=|{}.!])`)
 		return markdownV2Regex.ReplaceAllString(text, "\\$1")
 	}
 
-	now := time.Now().Format("[2006-01-02 15:04:05]")
-	jsonContext, _ := json.MarshalIndent(normalizeLogContext(context), "", "    ")
+	level := entry.Level
+	loggedAt := entry.Time.Format("[2006-01-02 15:04:05]")
+	jsonContext, _ := json.MarshalIndent(normalizeLogContext(entry.Fields), "", "    ")
 
 	title, ok := t.LevelTitles[level]
 	if !ok || title == "" {
@@ -83,8 +179,8 @@ func formatTelegramMarkdown(message string, context map[string]interface{}, leve
 
 	text := fmt.Sprintf("%s%s %s\n\n```json\n%s\n```",
 		decoration,
-		escapeMarkdownV2(now),
-		escapeMarkdownV2(message),
+		escapeMarkdownV2(loggedAt),
+		escapeMarkdownV2(entry.Message),
 		string(jsonContext))
 
 	return text
@@ -0,0 +1,65 @@
+package logger
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// TelegramPacer is a token bucket shared by everything sending to one chat. It allows burst messages at once
+// after a quiet period and perMinute messages per minute on average, and holds every message back for as long
+// as Telegram asked in retry_after.
+type TelegramPacer struct {
+	interval time.Duration
+	burst    float64
+
+	mu     sync.Mutex
+	tokens float64
+	// last is the time the tokens were counted at. After a retry_after it lies in the future,
+	// and no token is added before it.
+	last time.Time
+}
+
+// NewTelegramPacer returns a pacer allowing perMinute messages per minute with bursts of up to burst messages.
+func NewTelegramPacer(perMinute int, burst int) *TelegramPacer {
+	b := math.Max(1, float64(burst))
+	return &TelegramPacer{interval: time.Minute / time.Duration(max(perMinute, 1)), burst: b, tokens: b}
+}
+
+// reserve takes a token for a message and returns how long the caller has to wait before sending it.
+// If the wait would exceed limit, no token is taken and false is returned. A negative limit waits as long as needed.
+func (p *TelegramPacer) reserve(now time.Time, limit time.Duration) (time.Duration, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.last.IsZero() {
+		p.last = now
+	}
+	if now.After(p.last) {
+		p.tokens = math.Min(p.burst, p.tokens+float64(now.Sub(p.last))/float64(p.interval))
+		p.last = now
+	}
+
+	wait := p.last.Sub(now)
+	if p.tokens < 1 {
+		wait += time.Duration((1 - p.tokens) * float64(p.interval))
+	}
+	if limit >= 0 && wait > limit {
+		return wait, false
+	}
+
+	p.tokens--
+	return wait, true
+}
+
+// throttle holds messages back until d after now, when Telegram answered 429 Too Many Requests.
+// One message may be sent then, the next ones are paced again.
+func (p *TelegramPacer) throttle(now time.Time, d time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if until := now.Add(d); until.After(p.last) {
+		p.last = until
+		p.tokens = math.Min(p.tokens, 1)
+	}
+}
@@ -0,0 +1,328 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	composite_logger "github.com/Consolushka/golang.composite_logger/pkg"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	// telegramMessageLimit is the maximum length of a Telegram message; queued messages are coalesced up to it.
+	telegramMessageLimit = 4096
+	// maxThrottledAttempts bounds how often a message is retried after Telegram answered 429 Too Many Requests.
+	maxThrottledAttempts = 5
+)
+
+var (
+	// ErrTelegramQueueFull is returned when a message cannot be queued because the chat is throttled for too long.
+	ErrTelegramQueueFull = errors.New("telegram send queue full")
+	// ErrTelegramSenderClosed is returned for messages logged or left in the queue after the sender was closed.
+	ErrTelegramSenderClosed = errors.New("telegram sender closed")
+	// ErrTelegramThrottled is returned without sending when pacing or retry_after would hold a message back
+	// longer than TelegramLogger.MaxWait.
+	ErrTelegramThrottled = errors.New("telegram chat throttled")
+)
+
+// telegramMessage is a formatted entry waiting in the send queue, or a flush marker if flushed is set.
+type telegramMessage struct {
+	entry   composite_logger.Entry
+	text    string
+	report  func(entry composite_logger.Entry, err error)
+	flushed chan struct{}
+}
+
+// TelegramSender sends messages to one chat from a background goroutine. It paces them with a TelegramPacer,
+// waits as long as Telegram asks when it answers 429 Too Many Requests, and coalesces the messages queued meanwhile
+// into as few messages as possible, so a throttled chat never blocks the logger.
+type TelegramSender struct {
+	api    *tgbotapi.BotAPI
+	chatID int64
+	pacer  *TelegramPacer
+	queue  chan telegramMessage
+
+	closed    atomic.Bool
+	closeOnce sync.Once
+	stop      chan struct{}
+	stopped   chan struct{}
+}
+
+// NewTelegramSender starts a sender pacing messages to the chat with pacer, with room for queueSize
+// messages while it is throttled.
+func NewTelegramSender(api *tgbotapi.BotAPI, chatID int64, pacer *TelegramPacer, queueSize int) *TelegramSender {
+	s := &TelegramSender{
+		api:     api,
+		chatID:  chatID,
+		pacer:   pacer,
+		queue:   make(chan telegramMessage, queueSize),
+		stop:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go s.run()
+
+	return s
+}
+
+// enqueue queues a message without waiting. Send failures are passed to message.report later.
+func (s *TelegramSender) enqueue(message telegramMessage) error {
+	if s.closed.Load() {
+		return ErrTelegramSenderClosed
+	}
+
+	select {
+	case s.queue <- message:
+		return nil
+	default:
+		return ErrTelegramQueueFull
+	}
+}
+
+// Flush waits until every message queued before the call has been sent or given up on.
+func (s *TelegramSender) Flush(ctx context.Context) error {
+	if s.closed.Load() {
+		return nil
+	}
+
+	flushed := make(chan struct{})
+	select {
+	case s.queue <- telegramMessage{flushed: flushed}:
+	case <-s.stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case <-flushed:
+		return nil
+	case <-s.stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close sends the queued messages, giving up when ctx is done, and stops the sender.
+// Messages logged afterwards are rejected with ErrTelegramSenderClosed.
+func (s *TelegramSender) Close(ctx context.Context) error {
+	err := s.Flush(ctx)
+	s.closed.Store(true)
+	s.closeOnce.Do(func() { close(s.stop) })
+
+	select {
+	case <-s.stopped:
+	case <-ctx.Done():
+		if err == nil {
+			err = ctx.Err()
+		}
+	}
+
+	if unsent := len(s.queue); unsent > 0 {
+		err = errors.Join(err, fmt.Errorf("%w: %d messages not sent", ErrTelegramSenderClosed, unsent))
+	}
+
+	return err
+}
+
+func (s *TelegramSender) run() {
+	defer close(s.stopped)
+
+	var carry *telegramMessage
+	for {
+		first, ok := s.receive(carry)
+		if !ok {
+			return
+		}
+		carry = nil
+
+		if first.flushed != nil {
+			close(first.flushed)
+			continue
+		}
+
+		if !s.pace() {
+			first.report(first.entry, ErrTelegramSenderClosed)
+			return
+		}
+
+		batch, marker, rest := s.coalesce(first)
+		s.deliver(batch)
+		if marker != nil {
+			close(marker)
+		}
+		carry = rest
+	}
+}
+
+// receive returns the message left over from the previous batch or the next queued one.
+func (s *TelegramSender) receive(carry *telegramMessage) (telegramMessage, bool) {
+	if carry != nil {
+		return *carry, true
+	}
+
+	select {
+	case message := <-s.queue:
+		return message, true
+	case <-s.stop:
+		return telegramMessage{}, false
+	}
+}
+
+// pace waits until the next message may be sent. It reports false if the sender was closed meanwhile.
+func (s *TelegramSender) pace() bool {
+	wait, _ := s.pacer.reserve(time.Now(), -1)
+	return wait <= 0 || s.sleep(wait)
+}
+
+// coalesce adds queued messages to the batch while they fit into one Telegram message.
+// It stops at a flush marker, which is returned to be closed after the batch is sent,
+// and returns the first message that did not fit.
+func (s *TelegramSender) coalesce(first telegramMessage) ([]telegramMessage, chan struct{}, *telegramMessage) {
+	batch := []telegramMessage{first}
+	length := len(first.text)
+	for {
+		select {
+		case message := <-s.queue:
+			if message.flushed != nil {
+				return batch, message.flushed, nil
+			}
+			if length+2+len(message.text) > telegramMessageLimit {
+				return batch, nil, &message
+			}
+			batch = append(batch, message)
+			length += 2 + len(message.text)
+		default:
+			return batch, nil, nil
+		}
+	}
+}
+
+// deliver sends the batch as one message, waiting and retrying as long as Telegram asks when it is throttled.
+// Failures are reported for every entry of the batch.
+func (s *TelegramSender) deliver(batch []telegramMessage) {
+	texts := make([]string, len(batch))
+	messages := make([]string, len(batch))
+	for i, message := range batch {
+		texts[i] = message.text
+		messages[i] = message.entry.Message
+	}
+	text := strings.Join(texts, "\n\n")
+	plain := strings.Join(messages, "\n")
+
+	var err error
+	for attempt := 1; attempt <= maxThrottledAttempts; attempt++ {
+		err = sendTelegram(s.api, s.chatID, text, plain, true)
+		wait, throttled := retryAfter(err)
+		if !throttled {
+			break
+		}
+		s.pacer.throttle(time.Now(), wait)
+		if !s.pace() {
+			err = errors.Join(err, ErrTelegramSenderClosed)
+			break
+		}
+	}
+
+	if err == nil {
+		return
+	}
+	for _, message := range batch {
+		message.report(message.entry, err)
+	}
+}
+
+// sleep waits for d and reports false if the sender was closed meanwhile.
+func (s *TelegramSender) sleep(d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-s.stop:
+		return false
+	}
+}
+
+// TelegramError is returned for a message Telegram did not accept. It tells RetrySetting whether a retry
+// can succeed and how long Telegram asked to wait before it.
+type TelegramError struct {
+	ChatID int64
+	Err    error
+	// wait is how long a message held back with ErrTelegramThrottled would have had to wait.
+	wait time.Duration
+}
+
+// Error returns a description including the chat and the underlying error.
+func (e *TelegramError) Error() string {
+	return fmt.Sprintf("failed to send detailed log to ChatID %d: %v", e.ChatID, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *TelegramError) Unwrap() error {
+	return e.Err
+}
+
+// Transient reports whether sending again can succeed: Telegram throttled the bot (429), failed itself (5xx),
+// or the request did not get an answer from it at all. Rejected messages and bad credentials are not transient.
+func (e *TelegramError) Transient() bool {
+	var apiErr *tgbotapi.Error
+	if !errors.As(e.Err, &apiErr) {
+		return true
+	}
+
+	return apiErr.Code == 429 || apiErr.Code >= 500 || apiErr.RetryAfter > 0
+}
+
+// RetryAfter returns how long Telegram asked to wait before the next message, or zero.
+func (e *TelegramError) RetryAfter() time.Duration {
+	if e.wait > 0 {
+		return e.wait
+	}
+
+	wait, _ := retryAfter(e.Err)
+	return wait
+}
+
+// sendTelegram sends a MarkdownV2 message. If sending fails for a transient reason the error is returned as is,
+// because a fallback would fail as well. If the message is rejected and fallback is set,
+// plain is sent as a fallback without formatting and the error is still returned.
+func sendTelegram(api *tgbotapi.BotAPI, chatID int64, text string, plain string, fallback bool) error {
+	tgMessage := tgbotapi.NewMessage(chatID, text)
+	tgMessage.ParseMode = "MarkdownV2"
+
+	_, sendErr := api.Send(tgMessage)
+	if sendErr == nil {
+		return nil
+	}
+	err := &TelegramError{ChatID: chatID, Err: sendErr}
+	if !fallback || err.Transient() {
+		return err
+	}
+
+	// Fallback: send simple plain text message without Markdown
+	fallbackText := fmt.Sprintf("⚠️ [TelegramLogger Error]\nFailed to send detailed log.\nError: %v\nMessage: %s", sendErr, plain)
+	fallbackMsg := tgbotapi.NewMessage(chatID, fallbackText)
+	if _, fallbackErr := api.Send(fallbackMsg); fallbackErr != nil {
+		return errors.Join(err, fmt.Errorf("failed to send fallback message to ChatID %d: %w", chatID, fallbackErr))
+	}
+
+	return err
+}
+
+// retryAfter returns how long Telegram asked to wait if err reports 429 Too Many Requests.
+func retryAfter(err error) (time.Duration, bool) {
+	var apiErr *tgbotapi.Error
+	if !errors.As(err, &apiErr) || (apiErr.Code != 429 && apiErr.RetryAfter == 0) {
+		return 0, false
+	}
+
+	return time.Duration(max(apiErr.RetryAfter, 1)) * time.Second, true
+}
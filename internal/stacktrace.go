@@ -3,14 +3,45 @@ package internal
 import (
 	"fmt"
 	"runtime"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+const modulePath = "github.com/Consolushka/golang.composite_logger"
+
+// maxCachedPCs bounds the frame cache; program counters beyond it are resolved on every call.
+const maxCachedPCs = 4096
+
+var (
+	// frameCache maps a program counter to its frames, inlined calls included.
+	// It is replaced on write and read without locking.
+	frameCache   atomic.Pointer[map[uintptr][]runtime.Frame]
+	frameCacheMu sync.Mutex
+
+	// stackBufferPool holds the buffers stack traces are formatted into, so only the final string is allocated.
+	stackBufferPool = sync.Pool{
+		New: func() interface{} {
+			b := make([]byte, 0, 2048)
+			return &b
+		},
+	}
 )
 
 func BuildErrorContextWithStackTrace(ctx map[string]interface{}) map[string]interface{} {
 	context := CloneContext(ctx)
+	AddStackTrace(context)
 
+	return context
+}
+
+// AddStackTrace stores a stack trace under the "stackTrace" key unless the map already has one.
+// The trace embedded in the "error" value is preferred over the current goroutine stack.
+// Unlike BuildErrorContextWithStackTrace it modifies the map, which must not be nil.
+func AddStackTrace(context map[string]interface{}) {
 	if _, exists := context["stackTrace"]; exists {
-		return context
+		return
 	}
 
 	stackTrace := ExtractStackTraceFromError(context["error"])
@@ -19,21 +50,28 @@ func BuildErrorContextWithStackTrace(ctx map[string]interface{}) map[string]inte
 	}
 
 	context["stackTrace"] = stackTrace
-
-	return context
 }
 
 func CloneContext(ctx map[string]interface{}) map[string]interface{} {
-	if ctx == nil {
-		return map[string]interface{}{}
+	return MergeContext(ctx)
+}
+
+// MergeContext copies the given maps into a new one; later maps override keys of earlier ones.
+// It never returns nil and never modifies its arguments.
+func MergeContext(contexts ...map[string]interface{}) map[string]interface{} {
+	size := 0
+	for _, ctx := range contexts {
+		size += len(ctx)
 	}
 
-	cloned := make(map[string]interface{}, len(ctx))
-	for key, value := range ctx {
-		cloned[key] = value
+	merged := make(map[string]interface{}, size)
+	for _, ctx := range contexts {
+		for key, value := range ctx {
+			merged[key] = value
+		}
 	}
 
-	return cloned
+	return merged
 }
 
 func ExtractStackTraceFromError(errValue interface{}) string {
@@ -54,37 +92,117 @@ func BuildFallbackStackTrace() string {
 	const maxFrames = 48
 	const skipFrames = 3
 
-	pcs := make([]uintptr, maxFrames)
-	count := runtime.Callers(skipFrames, pcs)
-	if count == 0 {
-		return ""
+	var pcs [maxFrames]uintptr
+	count := runtime.Callers(skipFrames, pcs[:])
+
+	buf := stackBufferPool.Get().(*[]byte)
+	defer stackBufferPool.Put(buf)
+
+	b := (*buf)[:0]
+	for _, pc := range pcs[:count] {
+		for _, frame := range framesForPC(pc) {
+			if !ShouldIncludeFrame(frame.Function) {
+				continue
+			}
+			if len(b) > 0 {
+				b = append(b, '\n')
+			}
+			b = appendFrame(b, frame)
+		}
+	}
+	*buf = b
+
+	return string(b)
+}
+
+// ShouldIncludeFrame reports whether a frame belongs in a stack trace, i.e. whether it is not a library frame.
+func ShouldIncludeFrame(function string) bool {
+	return !IsLibraryFrame(function)
+}
+
+func FormatFrame(frame runtime.Frame) string {
+	return string(appendFrame(nil, frame))
+}
+
+func appendFrame(b []byte, frame runtime.Frame) []byte {
+	b = append(b, frame.Function...)
+	b = append(b, "\n\t"...)
+	b = append(b, frame.File...)
+	b = append(b, ':')
+
+	return strconv.AppendInt(b, int64(frame.Line), 10)
+}
+
+// CallerFrame returns the first frame outside of the library, i.e. the location of the log call.
+// Unwinding is paid per frame, so a short stack is inspected first; the library is only a few calls deep.
+func CallerFrame() (runtime.Frame, bool) {
+	const shortFrames = 8
+	const maxFrames = 32
+	const skipFrames = 2
+
+	var short [shortFrames]uintptr
+	if frame, ok := firstAppFrame(short[:runtime.Callers(skipFrames, short[:])]); ok {
+		return frame, true
+	}
+
+	var pcs [maxFrames]uintptr
+	return firstAppFrame(pcs[:runtime.Callers(skipFrames, pcs[:])])
+}
+
+// firstAppFrame returns the first frame of the program counters that does not belong to the library.
+func firstAppFrame(pcs []uintptr) (runtime.Frame, bool) {
+	for _, pc := range pcs {
+		for _, frame := range framesForPC(pc) {
+			if !IsLibraryFrame(frame.Function) {
+				return frame, true
+			}
+		}
 	}
 
-	frames := runtime.CallersFrames(pcs[:count])
-	lines := make([]string, 0, count)
+	return runtime.Frame{}, false
+}
 
+// framesForPC returns the frames of a return program counter as reported by runtime.Callers, innermost first.
+// Resolving frames dominates the cost of a log call, so they are cached per call site.
+func framesForPC(pc uintptr) []runtime.Frame {
+	if cache := frameCache.Load(); cache != nil {
+		if frames, ok := (*cache)[pc]; ok {
+			return frames
+		}
+	}
+
+	var frames []runtime.Frame
+	iter := runtime.CallersFrames([]uintptr{pc})
 	for {
-		frame, more := frames.Next()
+		frame, more := iter.Next()
+		frames = append(frames, frame)
 		if !more {
-			if ShouldIncludeFrame(frame.Function) {
-				lines = append(lines, FormatFrame(frame))
-			}
 			break
 		}
+	}
 
-		if ShouldIncludeFrame(frame.Function) {
-			lines = append(lines, FormatFrame(frame))
+	frameCacheMu.Lock()
+	defer frameCacheMu.Unlock()
+
+	var current map[uintptr][]runtime.Frame
+	if cache := frameCache.Load(); cache != nil {
+		current = *cache
+	}
+	if len(current) < maxCachedPCs {
+		next := make(map[uintptr][]runtime.Frame, len(current)+1)
+		for key, value := range current {
+			next[key] = value
 		}
+		next[pc] = frames
+		frameCache.Store(&next)
 	}
 
-	return strings.Join(lines, "\n")
-}
-
-func ShouldIncludeFrame(function string) bool {
-	return !strings.Contains(function, "github.com/Consolushka/golang.composite_logger/pkg/") &&
-		!strings.Contains(function, "github.com/Consolushka/golang.composite_logger/internal")
+	return frames
 }
 
-func FormatFrame(frame runtime.Frame) string {
-	return fmt.Sprintf("%s\n\t%s:%d", frame.Function, frame.File, frame.Line)
+// IsLibraryFrame reports whether the function belongs to the logger itself rather than to the application.
+func IsLibraryFrame(function string) bool {
+	return strings.HasPrefix(function, modulePath+"/pkg.") ||
+		strings.HasPrefix(function, modulePath+"/pkg/") ||
+		strings.HasPrefix(function, modulePath+"/internal")
 }
@@ -1,9 +1,11 @@
 package internal
 
 import (
+	"runtime"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCloneContext(t *testing.T) {
@@ -24,6 +26,17 @@ func TestCloneContext(t *testing.T) {
 	})
 }
 
+func TestMergeContext(t *testing.T) {
+	base := map[string]interface{}{"request_id": "r-1", "user": "alice"}
+	override := map[string]interface{}{"user": "bob"}
+
+	merged := MergeContext(base, nil, override)
+
+	assert.Equal(t, map[string]interface{}{"request_id": "r-1", "user": "bob"}, merged)
+	assert.Equal(t, "alice", base["user"], "arguments must not be modified")
+	assert.NotNil(t, MergeContext())
+}
+
 func TestShouldIncludeFrame(t *testing.T) {
 	tests := []struct {
 		function string
@@ -32,6 +45,8 @@ func TestShouldIncludeFrame(t *testing.T) {
 		{"main.main", true},
 		{"net/http.HandlerFunc.ServeHTTP", true},
 		{"github.com/Consolushka/golang.composite_logger/pkg/Info", false},
+		{"github.com/Consolushka/golang.composite_logger/pkg.(*CompositeLogger).Error", false},
+		{"github.com/Consolushka/golang.composite_logger/pkg.(*core).enqueue", false},
 		{"github.com/Consolushka/golang.composite_logger/pkg/ports/Setting.InitLogger", false},
 		{"github.com/Consolushka/golang.composite_logger/internal.BuildErrorContextWithStackTrace", false},
 		{"github.com/Consolushka/golang.composite_logger/internal/adapters/logger.TelegramLogger.send", false},
@@ -43,3 +58,49 @@ func TestShouldIncludeFrame(t *testing.T) {
 		})
 	}
 }
+
+func TestIsLibraryFrame(t *testing.T) {
+	tests := []struct {
+		function string
+		library  bool
+	}{
+		{"main.main", false},
+		{"github.com/Consolushka/golang.composite_logger/pkg.(*CompositeLogger).Info", true},
+		{"github.com/Consolushka/golang.composite_logger/pkg.Info", true},
+		{"github.com/Consolushka/golang.composite_logger/pkg/adapters/setting.ConsoleSetting.InitLogger", true},
+		{"github.com/Consolushka/golang.composite_logger/internal.CallerFrame", true},
+		{"github.com/Consolushka/golang.composite_logger/examples/composite.main", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.function, func(t *testing.T) {
+			assert.Equal(t, tt.library, IsLibraryFrame(tt.function))
+		})
+	}
+}
+
+func TestCallerFrame_ReturnsFrameOutsideLibrary(t *testing.T) {
+	frame, ok := CallerFrame()
+
+	// Test functions of this package are library frames, so the caller is the test runner.
+	assert.True(t, ok)
+	assert.False(t, IsLibraryFrame(frame.Function))
+}
+
+func TestFormatFrame(t *testing.T) {
+	frame := runtime.Frame{Function: "main.handler", File: "/app/main.go", Line: 42}
+
+	assert.Equal(t, "main.handler\n\t/app/main.go:42", FormatFrame(frame))
+}
+
+func TestFramesForPC_CachesResolvedFrames(t *testing.T) {
+	var pcs [1]uintptr
+	runtime.Callers(1, pcs[:])
+
+	first := framesForPC(pcs[0])
+	second := framesForPC(pcs[0])
+
+	require.NotEmpty(t, first)
+	assert.Equal(t, "github.com/Consolushka/golang.composite_logger/internal.TestFramesForPC_CachesResolvedFrames", first[0].Function)
+	assert.Same(t, &first[0], &second[0])
+}
@@ -0,0 +1,102 @@
+package composite_logger
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+const defaultQueueSize = 1000
+
+// AdapterSetting wraps a LoggerSetting with options for the adapter's dedicated delivery queue and worker.
+// Settings passed to Init or New without this wrapper use the defaults.
+//
+// Usage:
+//
+//	composite_logger.Init(composite_logger.AdapterSetting{
+//		Setting:   setting.TelegramSetting{Enabled: true, BotKey: "KEY", ChatId: 1},
+//		QueueSize: 100,
+//		Timeout:   5 * time.Second,
+//	})
+type AdapterSetting struct {
+	// Setting is the wrapped adapter configuration.
+	Setting LoggerSetting
+	// Name identifies the adapter in errors and health reports.
+	// It defaults to the setting type without the "Setting" suffix, e.g. "telegram"; it must be unique.
+	Name string
+	// QueueSize is the capacity of the adapter's own queue (default: 1000).
+	QueueSize int
+	// OverflowPolicy decides what happens when the adapter's queue is full (default: OverflowDropNewest).
+	// Dropping keeps the other adapters and the callers isolated from a slow or hung adapter at the cost of losing
+	// its entries; OverflowBlock delivers every entry but lets a hung adapter stall the others and then the callers.
+	OverflowPolicy OverflowPolicy
+	// BlockTimeout bounds the wait of OverflowBlockWithTimeout (default: 100ms).
+	BlockTimeout time.Duration
+	// Timeout bounds a single delivery to the adapter. Zero means no limit.
+	Timeout time.Duration
+	// MessageDecorator renders the message text the adapter receives. Nil passes the message unchanged.
+	MessageDecorator MessageDecorator
+	// Processors transform entries, in order, for this adapter only. They run after Options.Processors
+	// and before MessageDecorator.
+	Processors []Processor
+	// Sampling limits repeated entries for this adapter only. Nil disables sampling.
+	Sampling *Sampling
+	// RateLimit limits the entries per second this adapter receives. Nil disables the limit.
+	RateLimit *RateLimit
+	// Dedup collapses bursts of identical entries for this adapter. Nil delivers every entry.
+	Dedup *Dedup
+	// Spool keeps the entries for this adapter on disk until they are delivered, so they survive a restart.
+	// Nil keeps them in memory only.
+	Spool *Spool
+	// RoutedOnly makes the adapter receive only entries sent to it by one of Options.Routes,
+	// instead of also receiving the entries that match no route.
+	RoutedOnly bool
+}
+
+// InitLogger initializes the wrapped adapter.
+func (a AdapterSetting) InitLogger() Logger {
+	return a.Setting.InitLogger()
+}
+
+// IsEnabled reports whether the wrapped adapter is set and enabled.
+func (a AdapterSetting) IsEnabled() bool {
+	return a.Setting != nil && a.Setting.IsEnabled()
+}
+
+func (a AdapterSetting) overflowPolicy() OverflowPolicy {
+	if a.OverflowPolicy == 0 {
+		return OverflowDropNewest
+	}
+
+	return a.OverflowPolicy
+}
+
+// name returns the configured adapter name or one derived from the setting type.
+// Adapters wrapped in a RetrySetting are named after the wrapped setting.
+func (a AdapterSetting) name() string {
+	if a.Name != "" {
+		return a.Name
+	}
+	if retry, ok := a.Setting.(RetrySetting); ok {
+		return AdapterSetting{Setting: retry.Setting}.name()
+	}
+
+	t := reflect.TypeOf(a.Setting)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Name() == "" {
+		return "adapter"
+	}
+
+	return strings.ToLower(strings.TrimSuffix(t.Name(), "Setting"))
+}
+
+// adapterSetting returns the delivery options for s, unwrapping AdapterSetting if present.
+func adapterSetting(s LoggerSetting) AdapterSetting {
+	if as, ok := s.(AdapterSetting); ok {
+		return as
+	}
+
+	return AdapterSetting{Setting: s}
+}
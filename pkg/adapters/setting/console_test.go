@@ -1,9 +1,16 @@
 package setting
 
 import (
+	"bytes"
+	"errors"
+	"strings"
+	"sync"
 	"testing"
 
+	"github.com/Consolushka/golang.composite_logger/internal/adapters/logger"
 	composite_logger "github.com/Consolushka/golang.composite_logger/pkg"
+	"github.com/Consolushka/golang.composite_logger/pkg/ports"
+	"github.com/sirupsen/logrus"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -23,3 +30,48 @@ func TestConsoleSetting_InitLogger(t *testing.T) {
 		assert.NotNil(t, l)
 	})
 }
+
+func TestConsoleSetting_InitLogger_SupportsVerboseLevels(t *testing.T) {
+	l := ConsoleSetting{LowerLevel: composite_logger.DebugLevel}.InitLogger()
+
+	_, ok := l.(ports.VerboseLogger)
+	assert.True(t, ok)
+}
+
+// failingWriter rejects every write.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("disk full") }
+
+func TestConsoleLogger_TryLogReturnsWriteError(t *testing.T) {
+	l := logrus.New()
+	l.SetOutput(failingWriter{})
+	console := logger.NewConsoleLogger(l)
+
+	err := console.TryLog(composite_logger.Entry{Level: composite_logger.InfoLevel, Message: "lost"})
+
+	assert.ErrorContains(t, err, "disk full")
+}
+
+func TestConsoleLogger_TryLogSharesLogrusLock(t *testing.T) {
+	var out bytes.Buffer
+	l := logrus.New()
+	l.SetOutput(&out)
+	console := logger.NewConsoleLogger(l)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, console.TryLog(composite_logger.Entry{Level: composite_logger.InfoLevel, Message: "checked"}))
+		}()
+		go func() {
+			defer wg.Done()
+			console.Info("direct", nil)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 16, strings.Count(out.String(), "\n"))
+}
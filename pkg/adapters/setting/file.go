@@ -60,7 +60,7 @@ func (f FileSetting) InitLogger() ports.Logger {
 	mw := io.MultiWriter(os.Stdout, lumberjackLogger)
 	logrusInstance.SetOutput(mw)
 
-	return logger.NewFileLogger(logrusInstance)
+	return logger.NewFileLogger(logrusInstance, lumberjackLogger)
 }
 
 // setupRotation configures the lumberjack logger with defaults and user-provided values.
@@ -0,0 +1,34 @@
+package setting
+
+import (
+	"log/slog"
+
+	"github.com/Consolushka/golang.composite_logger/internal/adapters/logger"
+	compositelogger "github.com/Consolushka/golang.composite_logger/pkg"
+	"github.com/Consolushka/golang.composite_logger/pkg/ports"
+)
+
+// SlogSetting provides configuration for an adapter that writes to an arbitrary slog.Handler,
+// such as slog.NewJSONHandler or a third-party handler.
+type SlogSetting struct {
+	// Enabled toggles the slog logger on or off.
+	Enabled bool
+	// Handler receives the converted records.
+	Handler slog.Handler
+	// LowerLevel sets the minimum severity level to log. The handler's own Enabled check applies as well.
+	LowerLevel compositelogger.Level
+}
+
+// InitLogger initializes a logger that converts entries into slog records with typed attributes.
+func (s SlogSetting) InitLogger() ports.Logger {
+	if s.Handler == nil {
+		panic("Slog handler is not set")
+	}
+
+	return logger.NewSlogLogger(s.Handler, s.LowerLevel)
+}
+
+// IsEnabled returns the current active status of the adapter.
+func (s SlogSetting) IsEnabled() bool {
+	return s.Enabled
+}
@@ -0,0 +1,57 @@
+package setting
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	composite_logger "github.com/Consolushka/golang.composite_logger/pkg"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlogSetting_IsEnabled(t *testing.T) {
+	assert.True(t, SlogSetting{Enabled: true}.IsEnabled())
+	assert.False(t, SlogSetting{Enabled: false}.IsEnabled())
+}
+
+func TestSlogSetting_InitLogger_PanicOnMissingHandler(t *testing.T) {
+	assert.Panics(t, func() {
+		SlogSetting{}.InitLogger()
+	})
+}
+
+func TestSlogSetting_WritesTypedAttributes(t *testing.T) {
+	var buf bytes.Buffer
+	handler := slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+
+	cl, err := composite_logger.New(SlogSetting{
+		Enabled:    true,
+		Handler:    handler,
+		LowerLevel: composite_logger.InfoLevel,
+	})
+	require.NoError(t, err)
+
+	cl.Debug("filtered by LowerLevel", nil)
+	cl.Warn("payment declined", map[string]interface{}{
+		"error":   errors.New("card expired"),
+		"amount":  42,
+		"elapsed": 1500 * time.Millisecond,
+		"card":    map[string]interface{}{"brand": "visa", "last4": "4242"},
+	})
+	cl.Stop()
+
+	var record map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record), buf.String())
+
+	assert.Equal(t, "WARN", record["level"])
+	assert.Equal(t, "payment declined", record["msg"])
+	assert.Equal(t, "card expired", record["error"])
+	assert.InDelta(t, 42, record["amount"], 0)
+	assert.InDelta(t, float64(1500*time.Millisecond), record["elapsed"], 0)
+	assert.Equal(t, map[string]interface{}{"brand": "visa", "last4": "4242"}, record["card"])
+}
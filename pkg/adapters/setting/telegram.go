@@ -18,7 +18,9 @@ type TelegramSetting struct {
 	BotKey               string
 	// ChatId is the unique identifier for the target chat or user.
 	ChatId               int64
-	// Timeout sets the HTTP client timeout for API requests.
+	// Timeout sets the HTTP client timeout for API requests. Without a send queue it also bounds how long
+	// a message waits for pacing and retry_after (default: 5 seconds); a message that would wait longer
+	// fails with a transient error, so RetrySetting can try it again later.
 	Timeout              time.Duration
 	// LowerLevel sets the minimum severity level to log.
 	LowerLevel           compositelogger.Level
@@ -28,8 +30,24 @@ type TelegramSetting struct {
 	LevelWrappers        map[compositelogger.Level]string
 	// LevelTitles allows overriding default display names for log levels.
 	LevelTitles          map[compositelogger.Level]string
+	// MessagesPerMinute paces messages to the chat (default: 20 for group chats, 60 for private chats).
+	MessagesPerMinute    int
+	// MessagesBurst is the number of messages that may be sent at once after a quiet period (default: 3).
+	MessagesBurst        int
+	// SendQueueSize enables a send queue with room for this many messages. Messages are then paced, retry_after
+	// is respected without a limit and messages queued meanwhile are coalesced, but send failures are only
+	// reported to the error handler. Zero sends every message from the logger worker, waiting for pacing and
+	// retry_after up to Timeout, so throttling and other send failures reach RetrySetting, FailoverSetting and Spool.
+	SendQueueSize        int
 }
 
+const (
+	// defaultTelegramBurst is the number of messages sent at once after a quiet period when none is configured.
+	defaultTelegramBurst = 3
+	// defaultTelegramMaxWait bounds the wait for pacing and retry_after without a send queue when no Timeout is set.
+	defaultTelegramMaxWait = 5 * time.Second
+)
+
 var botAPIConstructor = tgbotapi.NewBotAPI
 
 // InitLogger initializes a Telegram-based logger with MarkdownV2 support.
@@ -64,6 +82,12 @@ func (t TelegramSetting) InitLogger() ports.Logger {
 		}
 	}
 
+	maxWait := t.Timeout
+	if maxWait <= 0 {
+		maxWait = defaultTelegramMaxWait
+	}
+
+	pacer := t.pacer()
 	return logger.TelegramLogger{
 		BotApi:               botApi,
 		LogChatId:            t.ChatId,
@@ -71,7 +95,38 @@ func (t TelegramSetting) InitLogger() ports.Logger {
 		UseLevelTitleWrapper: useLevelTitleWrapper,
 		LevelWrappers:        finalWrappers,
 		LevelTitles:          t.LevelTitles,
+		Sender:               t.sender(botApi, pacer),
+		Pacer:                pacer,
+		MaxWait:              maxWait,
+	}
+}
+
+// pacer returns the token bucket pacing messages to the chat.
+func (t TelegramSetting) pacer() *logger.TelegramPacer {
+	perMinute := t.MessagesPerMinute
+	if perMinute <= 0 {
+		// Group chats have negative IDs and a lower limit than private chats.
+		perMinute = 60
+		if t.ChatId < 0 {
+			perMinute = 20
+		}
 	}
+
+	burst := t.MessagesBurst
+	if burst <= 0 {
+		burst = defaultTelegramBurst
+	}
+
+	return logger.NewTelegramPacer(perMinute, burst)
+}
+
+// sender returns the queue sending paced messages to the chat, or nil unless SendQueueSize is positive.
+func (t TelegramSetting) sender(botApi *tgbotapi.BotAPI, pacer *logger.TelegramPacer) *logger.TelegramSender {
+	if t.SendQueueSize <= 0 {
+		return nil
+	}
+
+	return logger.NewTelegramSender(botApi, t.ChatId, pacer, t.SendQueueSize)
 }
 
 // IsEnabled returns the current active status of the adapter.
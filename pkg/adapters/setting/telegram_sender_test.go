@@ -0,0 +1,231 @@
+package setting
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Consolushka/golang.composite_logger/internal/adapters/logger"
+	composite_logger "github.com/Consolushka/golang.composite_logger/pkg"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTelegram is a Bot API server answering sendMessage with the status returned by respond.
+type fakeTelegram struct {
+	mu      sync.Mutex
+	texts   []string
+	respond func(call int) (status int, retryAfter int)
+}
+
+func (f *fakeTelegram) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	_ = r.ParseForm()
+	f.mu.Lock()
+	f.texts = append(f.texts, r.PostForm.Get("text"))
+	status, retryAfter := f.respond(len(f.texts))
+	f.mu.Unlock()
+
+	if status == http.StatusTooManyRequests {
+		_, _ = fmt.Fprintf(w, `{"ok":false,"error_code":429,"description":"Too Many Requests: retry after %d","parameters":{"retry_after":%d}}`, retryAfter, retryAfter)
+		return
+	}
+	if status != http.StatusOK {
+		_, _ = fmt.Fprintf(w, `{"ok":false,"error_code":%d,"description":"%s"}`, status, http.StatusText(status))
+		return
+	}
+	_, _ = w.Write([]byte(`{"ok":true,"result":{"message_id":1,"date":0,"chat":{"id":1}}}`))
+}
+
+func (f *fakeTelegram) sent() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.texts...)
+}
+
+// initTelegram initializes the setting against the fake server.
+func initTelegram(t *testing.T, server *fakeTelegram, s TelegramSetting) logger.TelegramLogger {
+	serveTelegram(t, server)
+
+	s.Enabled = true
+	s.BotKey = "token"
+	return s.InitLogger().(logger.TelegramLogger)
+}
+
+// serveTelegram makes Telegram settings initialized during the test talk to the fake server.
+func serveTelegram(t *testing.T, server *fakeTelegram) {
+	srv := httptest.NewServer(server)
+	t.Cleanup(srv.Close)
+
+	oldConstructor := botAPIConstructor
+	t.Cleanup(func() { botAPIConstructor = oldConstructor })
+	botAPIConstructor = func(token string) (*tgbotapi.BotAPI, error) {
+		api := &tgbotapi.BotAPI{Token: token, Client: srv.Client()}
+		api.SetAPIEndpoint(srv.URL + "/bot%s/%s")
+		return api, nil
+	}
+}
+
+func entry(message string) composite_logger.Entry {
+	return composite_logger.Entry{Level: composite_logger.ErrorLevel, Time: time.Now(), Message: message}
+}
+
+func TestTelegramSender_WaitsForRetryAfterAndCoalesces(t *testing.T) {
+	server := &fakeTelegram{respond: func(call int) (int, int) {
+		if call == 1 {
+			return http.StatusTooManyRequests, 1
+		}
+		return http.StatusOK, 0
+	}}
+	var reported []error
+	tg := initTelegram(t, server, TelegramSetting{ChatId: 1, MessagesPerMinute: 6000, SendQueueSize: 10})
+	tg.ErrorHandler = func(_ composite_logger.Entry, err error) { reported = append(reported, err) }
+
+	start := time.Now()
+	for _, message := range []string{"first", "second", "third"} {
+		require.NoError(t, tg.TryLog(entry(message)))
+	}
+	assert.Less(t, time.Since(start), 500*time.Millisecond, "queuing does not wait for Telegram")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, tg.Close(ctx))
+
+	texts := server.sent()
+	assert.GreaterOrEqual(t, time.Since(start), time.Second, "retry_after is respected")
+	assert.LessOrEqual(t, len(texts), 3, "the throttled message is retried once and the rest coalesced")
+	delivered := strings.Join(texts[1:], "\n")
+	for _, message := range []string{"first", "second", "third"} {
+		assert.Contains(t, delivered, message)
+	}
+	for _, text := range texts {
+		assert.NotContains(t, text, "[TelegramLogger Error]", "no fallback is sent while throttled")
+	}
+	assert.Empty(t, reported)
+}
+
+func TestTelegramSender_RejectsWhenQueueIsFull(t *testing.T) {
+	received := make(chan struct{}, 10)
+	server := &fakeTelegram{respond: func(int) (int, int) {
+		received <- struct{}{}
+		return http.StatusTooManyRequests, 30
+	}}
+	tg := initTelegram(t, server, TelegramSetting{ChatId: -100, SendQueueSize: 1})
+	tg.ErrorHandler = func(composite_logger.Entry, error) {}
+
+	require.NoError(t, tg.TryLog(entry("throttled")))
+	<-received
+	require.NoError(t, tg.TryLog(entry("queued")))
+	assert.ErrorIs(t, tg.TryLog(entry("rejected")), logger.ErrTelegramQueueFull)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.Error(t, tg.Close(ctx), "messages still queued when the deadline expires are reported")
+	assert.ErrorIs(t, tg.TryLog(entry("late")), logger.ErrTelegramSenderClosed)
+}
+
+func TestTelegramSetting_WithoutQueueReturnsThrottlingErrorBeyondTimeout(t *testing.T) {
+	server := &fakeTelegram{respond: func(int) (int, int) { return http.StatusTooManyRequests, 3 }}
+	tg := initTelegram(t, server, TelegramSetting{ChatId: 1, Timeout: time.Second})
+	assert.Nil(t, tg.Sender, "the send queue is opt-in")
+
+	start := time.Now()
+	err := tg.TryLog(entry("throttled"))
+
+	assert.ErrorContains(t, err, "Too Many Requests")
+	assert.Less(t, time.Since(start), time.Second, "a retry_after beyond Timeout is not waited for")
+	assert.Len(t, server.sent(), 1, "no fallback is sent while throttled")
+
+	err = tg.TryLog(entry("held back"))
+	assert.ErrorIs(t, err, logger.ErrTelegramThrottled)
+	var tgErr *logger.TelegramError
+	require.ErrorAs(t, err, &tgErr)
+	assert.True(t, tgErr.Transient())
+	assert.Greater(t, tgErr.RetryAfter(), time.Second)
+	assert.Len(t, server.sent(), 1, "the chat is not asked again before retry_after")
+}
+
+func TestTelegramSetting_WithoutQueueWaitsForRetryAfter(t *testing.T) {
+	server := &fakeTelegram{respond: func(call int) (int, int) {
+		if call == 1 {
+			return http.StatusTooManyRequests, 1
+		}
+		return http.StatusOK, 0
+	}}
+	tg := initTelegram(t, server, TelegramSetting{ChatId: 1})
+
+	start := time.Now()
+	require.NoError(t, tg.TryLog(entry("throttled")))
+
+	assert.GreaterOrEqual(t, time.Since(start), time.Second, "retry_after is respected")
+	assert.Len(t, server.sent(), 2)
+}
+
+func TestTelegramSetting_WithoutQueuePacesAfterBurst(t *testing.T) {
+	server := &fakeTelegram{respond: func(int) (int, int) { return http.StatusOK, 0 }}
+	tg := initTelegram(t, server, TelegramSetting{ChatId: 1, MessagesPerMinute: 600, MessagesBurst: 2})
+
+	start := time.Now()
+	for _, message := range []string{"first", "second"} {
+		require.NoError(t, tg.TryLog(entry(message)))
+	}
+	assert.Less(t, time.Since(start), 100*time.Millisecond, "a burst is sent at once")
+
+	for _, message := range []string{"third", "fourth"} {
+		require.NoError(t, tg.TryLog(entry(message)))
+	}
+	assert.GreaterOrEqual(t, time.Since(start), 190*time.Millisecond, "further messages are paced")
+	assert.Len(t, server.sent(), 4)
+}
+
+func TestTelegramSetting_RetriesOnlyTransientErrors(t *testing.T) {
+	statuses := map[int]int{1: http.StatusBadRequest, 2: http.StatusBadGateway, 3: http.StatusTooManyRequests}
+	server := &fakeTelegram{respond: func(call int) (int, int) {
+		if status, ok := statuses[call]; ok {
+			return status, 1
+		}
+		return http.StatusOK, 0
+	}}
+	serveTelegram(t, server)
+	tg := composite_logger.RetrySetting{
+		Setting: TelegramSetting{Enabled: true, BotKey: "token", ChatId: 1, MessagesPerMinute: 6000},
+		Backoff: time.Millisecond,
+	}.InitLogger().(composite_logger.CheckedEntryLogger)
+
+	err := tg.TryLog(entry("rejected"))
+	var tgErr *logger.TelegramError
+	require.ErrorAs(t, err, &tgErr)
+	assert.False(t, tgErr.Transient())
+	assert.NotErrorIs(t, err, composite_logger.ErrRetriesExhausted)
+	assert.Len(t, server.sent(), 1, "a rejected message is neither retried nor followed by a fallback")
+
+	start := time.Now()
+	require.NoError(t, tg.TryLog(entry("retried")))
+	assert.GreaterOrEqual(t, time.Since(start), time.Second, "retry_after is respected")
+	texts := server.sent()
+	require.Len(t, texts, 4, "502 and 429 are retried")
+	for _, text := range texts {
+		assert.NotContains(t, text, "[TelegramLogger Error]")
+	}
+}
+
+func TestTelegramSetting_SendsFallbackForRejectedMessages(t *testing.T) {
+	server := &fakeTelegram{respond: func(call int) (int, int) {
+		if call == 1 {
+			return http.StatusBadRequest, 0
+		}
+		return http.StatusOK, 0
+	}}
+	tg := initTelegram(t, server, TelegramSetting{ChatId: 1})
+
+	assert.Error(t, tg.TryLog(entry("rejected")))
+	texts := server.sent()
+	require.Len(t, texts, 2)
+	assert.Contains(t, texts[1], "[TelegramLogger Error]")
+}
@@ -38,6 +38,7 @@ func TestTelegramSetting_InitLogger(t *testing.T) {
 		assert.True(t, ok)
 		assert.True(t, tgLogger.UseLevelTitleWrapper)
 		assert.Equal(t, composite_logger.DefaultLevelWrappers[composite_logger.InfoLevel], tgLogger.LevelWrappers[composite_logger.InfoLevel])
+		assert.Equal(t, composite_logger.DefaultLevelWrappers[composite_logger.DebugLevel], tgLogger.LevelWrappers[composite_logger.DebugLevel])
 	})
 
 	t.Run("success with custom wrappers overrides", func(t *testing.T) {
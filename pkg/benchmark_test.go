@@ -0,0 +1,328 @@
+package composite_logger
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Consolushka/golang.composite_logger/internal"
+)
+
+// discardLogger drops every message, so the benchmarks measure the logger rather than the destination.
+type discardLogger struct{}
+
+func (discardLogger) Info(string, map[string]interface{})  {}
+func (discardLogger) Warn(string, map[string]interface{})  {}
+func (discardLogger) Error(string, map[string]interface{}) {}
+func (discardLogger) Fatal(string, map[string]interface{}) {}
+
+// mutexLogger reproduces the original dispatch path for comparison:
+// every call takes a global mutex for the whole channel send and Error clones the context to add a stack trace.
+// Like the composite logger it stamps entries with the time and the cached caller location,
+// so the comparison measures dispatch rather than the extra data entries carry today.
+type mutexLogger struct {
+	mu      sync.Mutex
+	ch      chan Entry
+	loggers []Logger
+	wg      sync.WaitGroup
+}
+
+func newMutexLogger(loggers ...Logger) *mutexLogger {
+	ml := &mutexLogger{ch: make(chan Entry, defaultQueueSize), loggers: loggers}
+	ml.wg.Add(1)
+	go func() {
+		defer ml.wg.Done()
+		for entry := range ml.ch {
+			for _, l := range ml.loggers {
+				legacyLogger{logger: l}.Log(entry)
+			}
+		}
+	}()
+
+	return ml
+}
+
+func (ml *mutexLogger) log(level Level, msg string, ctx map[string]interface{}) {
+	if level >= ErrorLevel {
+		ctx = internal.BuildErrorContextWithStackTrace(ctx)
+	}
+
+	entry := Entry{Level: level, Time: time.Now(), Message: "[" + level.String() + "] " + msg, Fields: ctx}
+	if frame, ok := internal.CallerFrame(); ok {
+		entry.Caller = Caller{Function: frame.Function, File: frame.File, Line: frame.Line}
+	}
+
+	ml.mu.Lock()
+	defer ml.mu.Unlock()
+	ml.ch <- entry
+}
+
+func (ml *mutexLogger) stop() {
+	close(ml.ch)
+	ml.wg.Wait()
+}
+
+var benchmarkFields = map[string]interface{}{"requestId": "abc-123", "attempt": 3}
+
+func BenchmarkInfo(b *testing.B) {
+	b.Run("composite", func(b *testing.B) {
+		cl, err := New(testSetting{discardLogger{}})
+		if err != nil {
+			b.Fatal(err)
+		}
+		defer cl.Stop()
+
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				cl.Info("request handled", benchmarkFields)
+			}
+		})
+	})
+
+	b.Run("mutex", func(b *testing.B) {
+		ml := newMutexLogger(discardLogger{})
+		defer ml.stop()
+
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				ml.log(InfoLevel, "request handled", benchmarkFields)
+			}
+		})
+	})
+}
+
+// discardAttrLogger drops every entry and reads typed fields without a map.
+type discardAttrLogger struct{ discardLogger }
+
+func (discardAttrLogger) Log(Entry)            {}
+func (discardAttrLogger) LogAttrs(Entry) error { return nil }
+
+// BenchmarkInfoFields compares a context map built per call with typed fields.
+func BenchmarkInfoFields(b *testing.B) {
+	b.Run("map", func(b *testing.B) {
+		cl, err := New(testSetting{discardAttrLogger{}})
+		if err != nil {
+			b.Fatal(err)
+		}
+		defer cl.Stop()
+
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				cl.Info("request handled", map[string]interface{}{"requestId": "abc-123", "attempt": 3, "took": time.Millisecond})
+			}
+		})
+	})
+
+	b.Run("typed", func(b *testing.B) {
+		cl, err := New(testSetting{discardAttrLogger{}})
+		if err != nil {
+			b.Fatal(err)
+		}
+		defer cl.Stop()
+
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				cl.InfoFields("request handled", F.String("requestId", "abc-123"), F.Int("attempt", 3), F.Duration("took", time.Millisecond))
+			}
+		})
+	})
+}
+
+func BenchmarkInfoGlobal(b *testing.B) {
+	Init(testSetting{discardLogger{}})
+	defer Stop()
+
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			Info("request handled", benchmarkFields)
+		}
+	})
+}
+
+func BenchmarkError(b *testing.B) {
+	b.Run("composite", func(b *testing.B) {
+		cl, err := New(testSetting{discardLogger{}})
+		if err != nil {
+			b.Fatal(err)
+		}
+		defer cl.Stop()
+
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				cl.Error("request failed", benchmarkFields)
+			}
+		})
+	})
+
+	b.Run("mutex", func(b *testing.B) {
+		ml := newMutexLogger(discardLogger{})
+		defer ml.stop()
+
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				ml.log(ErrorLevel, "request failed", benchmarkFields)
+			}
+		})
+	})
+}
+
+// BenchmarkQueue compares handing entries over by value, as the queues do, with pooled entries passed by pointer.
+// Entries are copied into each adapter queue anyway, so pooling only adds the pool round trip.
+func BenchmarkQueue(b *testing.B) {
+	b.Run("value", func(b *testing.B) {
+		ch := make(chan Entry, defaultQueueSize)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for entry := range ch {
+				discardLogger{}.Info(entry.Message, entry.Fields)
+			}
+		}()
+
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			ch <- Entry{Level: InfoLevel, Time: time.Now(), Message: "request handled", Fields: benchmarkFields}
+		}
+		close(ch)
+		<-done
+	})
+
+	b.Run("pooled", func(b *testing.B) {
+		pool := sync.Pool{New: func() interface{} { return new(Entry) }}
+		ch := make(chan *Entry, defaultQueueSize)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for entry := range ch {
+				discardLogger{}.Info(entry.Message, entry.Fields)
+				*entry = Entry{}
+				pool.Put(entry)
+			}
+		}()
+
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			entry := pool.Get().(*Entry)
+			*entry = Entry{Level: InfoLevel, Time: time.Now(), Message: "request handled", Fields: benchmarkFields}
+			ch <- entry
+		}
+		close(ch)
+		<-done
+	})
+}
+
+func BenchmarkWith(b *testing.B) {
+	cl, err := New(testSetting{discardLogger{}})
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer cl.Stop()
+	child := cl.Named("billing").With(map[string]interface{}{"component": "invoices"})
+
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			child.Info("invoice sent", benchmarkFields)
+		}
+	})
+}
+
+// uncachedCallerFrame resolves the caller like the original implementation, walking the symbol tables on every call.
+func uncachedCallerFrame() (runtime.Frame, bool) {
+	var pcs [16]uintptr
+	count := runtime.Callers(2, pcs[:])
+	frames := runtime.CallersFrames(pcs[:count])
+	for {
+		frame, more := frames.Next()
+		if !internal.IsLibraryFrame(frame.Function) {
+			return frame, true
+		}
+		if !more {
+			return runtime.Frame{}, false
+		}
+	}
+}
+
+// uncachedStackTrace formats the stack like the original implementation.
+func uncachedStackTrace() string {
+	pcs := make([]uintptr, 48)
+	count := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:count])
+	lines := make([]string, 0, count)
+	for {
+		frame, more := frames.Next()
+		if internal.ShouldIncludeFrame(frame.Function) {
+			lines = append(lines, fmt.Sprintf("%s\n\t%s:%d", frame.Function, frame.File, frame.Line))
+		}
+		if !more {
+			break
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func BenchmarkCallerFrame(b *testing.B) {
+	b.Run("cached", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			internal.CallerFrame()
+		}
+	})
+
+	b.Run("uncached", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			uncachedCallerFrame()
+		}
+	})
+}
+
+func BenchmarkStackTrace(b *testing.B) {
+	b.Run("pooled", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			internal.BuildFallbackStackTrace()
+		}
+	})
+
+	b.Run("sprintf", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			uncachedStackTrace()
+		}
+	})
+}
+
+func BenchmarkDefault(b *testing.B) {
+	Init(testSetting{discardLogger{}})
+	defer Stop()
+
+	b.Run("atomic", func(b *testing.B) {
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				Default()
+			}
+		})
+	})
+
+	b.Run("mutex", func(b *testing.B) {
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				mu.Lock()
+				_ = instance.Load()
+				mu.Unlock()
+			}
+		})
+	})
+}
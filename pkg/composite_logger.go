@@ -1,88 +1,458 @@
 package composite_logger
 
 import (
+	"errors"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/Consolushka/golang.composite_logger/internal"
 	"github.com/Consolushka/golang.composite_logger/pkg/ports"
 )
 
 var (
-	instance *CompositeLogger
+	// instance is read without locking on every package-level log call; mu serializes replacing it.
+	instance atomic.Pointer[CompositeLogger]
 	mu       sync.Mutex
 )
 
 type Logger = ports.Logger
 type LoggerSetting = ports.LoggerSetting
 
-type logEntry struct {
-	level   Level
-	message string
-	context map[string]interface{}
-}
+// LoggerKey is the field under which the hierarchical name of a logger created by Named is logged.
+const LoggerKey = "logger"
 
 // CompositeLogger manages a collection of loggers and handles asynchronous log dispatching.
+// Instances are independent of each other and of the global logger used by the package-level functions.
+// Every adapter is served by its own queue and worker, so a slow destination only delays itself.
+//
+// Loggers returned by With and Named share the adapters and queues of their parent
+// and add their bound fields and name to every entry.
 type CompositeLogger struct {
-	loggers []ports.Logger
-	ch      chan logEntry
-	wg      sync.WaitGroup
+	// core is nil for loggers derived from the package-level functions; they resolve the current default on every call.
+	core    *core
+	derived bool
+	name    string
+	fields  map[string]interface{}
+}
+
+// New creates a standalone CompositeLogger with the provided settings and default Options.
+// It returns an error if any enabled adapter fails to initialize.
+//
+// Usage:
+//
+//	logger, err := composite_logger.New(setting.ConsoleSetting{Enabled: true, LowerLevel: composite_logger.InfoLevel})
+//	if err != nil {
+//		return err
+//	}
+//	defer logger.Stop()
+func New(settings ...LoggerSetting) (*CompositeLogger, error) {
+	return NewWithOptions(Options{}, settings...)
+}
+
+// NewWithOptions creates a standalone CompositeLogger with the provided options and settings
+// and starts its background workers.
+// It returns an error if any enabled adapter fails to initialize.
+//
+// Usage:
+//
+//	logger, err := composite_logger.NewWithOptions(composite_logger.Options{QueueSize: 5000}, setting.ConsoleSetting{Enabled: true})
+func NewWithOptions(opts Options, settings ...LoggerSetting) (*CompositeLogger, error) {
+	c, err := newCore(opts, settings...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CompositeLogger{core: c}, nil
 }
 
 // Init initializes the global logger instance with the provided settings.
 // If an instance already exists, it will be gracefully shut down before re-initialization.
+// Init panics if any enabled adapter fails to initialize; use New to handle the error instead.
 //
 // Usage:
 //
 //	composite_logger.Init(setting.ConsoleSetting{LowerLevel: composite_logger.InfoLevel}, setting.TelegramSetting{LowerLevel: composite_logger.InfoLevel, Enabled: true})
 func Init(settings ...LoggerSetting) {
+	InitWithOptions(Options{}, settings...)
+}
+
+// InitWithOptions initializes the global logger instance with the provided options and settings.
+// It behaves like Init otherwise. If the previous instance still owns a spool directory the new one uses,
+// it is stopped before the new instance is created, so the directory is handed over.
+//
+// Usage:
+//
+//	composite_logger.InitWithOptions(composite_logger.Options{OverflowPolicy: composite_logger.OverflowDropNewest}, setting.ConsoleSetting{Enabled: true})
+func InitWithOptions(opts Options, settings ...LoggerSetting) {
 	mu.Lock()
 	defer mu.Unlock()
 
+	cl, err := NewWithOptions(opts, settings...)
+	if previous := instance.Load(); previous != nil && errors.Is(err, ErrSpoolInUse) {
+		previous.Stop()
+		cl, err = NewWithOptions(opts, settings...)
+	}
+	if err != nil {
+		panic(err)
+	}
+
 	// In case of re-initializing composite logger
-	if instance != nil {
-		instance.flushAndClose()
+	if previous := instance.Swap(cl); previous != nil {
+		previous.Stop()
 	}
+}
 
-	loggers := make([]ports.Logger, 0, len(settings))
-	for _, s := range settings {
-		if s.IsEnabled() {
-			loggers = append(loggers, s.InitLogger())
-		}
+// Default returns the logger used by the package-level functions, or nil if none is set.
+func Default() *CompositeLogger {
+	return instance.Load()
+}
+
+// SetDefault replaces the logger used by the package-level functions.
+// The previous default is not stopped; its owner remains responsible for calling Stop on it.
+//
+// Usage:
+//
+//	logger, _ := composite_logger.New(setting.ConsoleSetting{Enabled: true})
+//	composite_logger.SetDefault(logger)
+func SetDefault(cl *CompositeLogger) {
+	mu.Lock()
+	defer mu.Unlock()
+	instance.Store(cl)
+}
+
+// With returns a logger that adds the given fields to every entry.
+// It shares the adapters and queues of cl; fields passed to a log call take precedence over bound ones.
+//
+// Usage:
+//
+//	billing := logger.With(map[string]interface{}{"component": "billing"})
+func (cl *CompositeLogger) With(fields map[string]interface{}) *CompositeLogger {
+	if cl == nil {
+		return nil
+	}
+
+	child := cl.derive()
+	if len(fields) > 0 {
+		child.fields = internal.MergeContext(cl.fields, fields)
+	}
+
+	return child
+}
+
+// Named returns a logger whose entries carry a hierarchical name under the "logger" key.
+// Names of nested loggers are joined with dots, e.g. "billing.invoices".
+//
+// Usage:
+//
+//	invoices := logger.Named("billing").Named("invoices")
+func (cl *CompositeLogger) Named(name string) *CompositeLogger {
+	if cl == nil {
+		return nil
 	}
 
-	instance = &CompositeLogger{
-		loggers: loggers,
-		ch:      make(chan logEntry, 1000),
+	child := cl.derive()
+	switch {
+	case name == "":
+	case cl.name == "":
+		child.name = name
+	default:
+		child.name = cl.name + "." + name
 	}
 
-	instance.wg.Add(1)
-	go instance.listenAndBroadcast()
+	return child
 }
 
-// listenAndBroadcast is a background worker that processes the log queue
-// and sends entries to all registered adapters.
-func (cl *CompositeLogger) listenAndBroadcast() {
-	defer cl.wg.Done()
-	for entry := range cl.ch {
-		for _, logger := range cl.loggers {
-			switch entry.level {
-			case InfoLevel:
-				logger.Info(entry.message, entry.context)
-			case WarningLevel:
-				logger.Warn(entry.message, entry.context)
-			case ErrorLevel:
-				logger.Error(entry.message, entry.context)
-			case FatalLevel:
-				logger.Fatal(entry.message, entry.context)
+// Name returns the hierarchical name of the logger, or an empty string for root loggers.
+func (cl *CompositeLogger) Name() string {
+	if cl == nil {
+		return ""
+	}
+
+	return cl.name
+}
+
+func (cl *CompositeLogger) derive() *CompositeLogger {
+	return &CompositeLogger{
+		core:    cl.core,
+		derived: true,
+		name:    cl.name,
+		fields:  cl.fields,
+	}
+}
+
+// resolve returns the core entries are sent to, together with the logger whose bound fields apply first.
+// Loggers derived from the package-level functions use the current default, so they survive re-initialization.
+func (cl *CompositeLogger) resolve() (*core, *CompositeLogger) {
+	if cl == nil {
+		return nil, nil
+	}
+	if cl.core != nil || !cl.derived {
+		return cl.core, nil
+	}
+
+	base := Default()
+	if base == nil || base.core == nil {
+		return nil, nil
+	}
+
+	return base.core, base
+}
+
+// log builds an entry for the call and hands it over to the core.
+func (cl *CompositeLogger) log(level Level, msg string, ctx map[string]interface{}) {
+	c, base := cl.resolve()
+	if c == nil {
+		return
+	}
+
+	cl.emit(c, base, newEntry(level, msg, ctx))
+}
+
+// logFields builds an entry with typed fields for the call and hands it over to the core.
+func (cl *CompositeLogger) logFields(level Level, msg string, fields []Field) {
+	c, base := cl.resolve()
+	if c == nil {
+		return
+	}
+
+	entry := newEntry(level, msg, nil)
+	entry.Attrs = fields
+	entry.typed = true
+	cl.emit(c, base, entry)
+}
+
+// emit completes the entry and hands it over to the core.
+func (cl *CompositeLogger) emit(c *core, base *CompositeLogger, entry Entry) {
+	entry = cl.prepare(base, entry)
+	if entry.Level == FatalLevel {
+		c.fatal(entry)
+		return
+	}
+
+	c.enqueue(entry)
+}
+
+// newEntry creates an entry stamped with the current time and the location of the log call.
+func newEntry(level Level, msg string, ctx map[string]interface{}) Entry {
+	entry := Entry{
+		Level:   level,
+		Time:    time.Now(),
+		Message: msg,
+		Fields:  ctx,
+	}
+
+	if frame, ok := internal.CallerFrame(); ok {
+		entry.Caller = Caller{Function: frame.Function, File: frame.File, Line: frame.Line}
+	}
+
+	return entry
+}
+
+// prepare completes the entry with the bound fields and name of the logger.
+// Error and Fatal entries get a stack trace, see internal.AddStackTrace.
+// The fields are copied at most once, and only if something has to be added to them.
+func (cl *CompositeLogger) prepare(base *CompositeLogger, entry Entry) Entry {
+	if entry.typed {
+		return cl.prepareTyped(base, entry)
+	}
+
+	var baseFields map[string]interface{}
+	entry.Logger = cl.name
+	if base != nil {
+		baseFields = base.fields
+		entry.Logger = joinNames(base.name, cl.name)
+	}
+
+	var nameField map[string]interface{}
+	if entry.Logger != "" {
+		nameField = map[string]interface{}{LoggerKey: entry.Logger}
+	}
+
+	withStack := entry.Level >= ErrorLevel
+	if len(baseFields) > 0 || len(cl.fields) > 0 || nameField != nil || withStack {
+		entry.Fields = internal.MergeContext(baseFields, cl.fields, entry.Fields, nameField)
+	}
+
+	if withStack {
+		internal.AddStackTrace(entry.Fields)
+		entry.Stack, _ = entry.Fields["stackTrace"].(string)
+	}
+
+	return entry
+}
+
+// prepareTyped completes an entry with typed fields. The bound fields are shared rather than copied,
+// and the name and stack trace are only set on the entry; Entry.FieldsMap adds them to the map when an adapter needs one.
+func (cl *CompositeLogger) prepareTyped(base *CompositeLogger, entry Entry) Entry {
+	entry.Fields = cl.fields
+	entry.Logger = cl.name
+	if base != nil {
+		entry.Logger = joinNames(base.name, cl.name)
+		if len(base.fields) > 0 {
+			entry.Fields = base.fields
+			if len(cl.fields) > 0 {
+				entry.Fields = internal.MergeContext(base.fields, cl.fields)
 			}
 		}
 	}
+
+	if entry.Level >= ErrorLevel {
+		entry.Stack = internal.ExtractStackTraceFromError(errorFromAttrs(entry.Attrs))
+		if entry.Stack == "" {
+			entry.Stack = internal.BuildFallbackStackTrace()
+		}
+	}
+
+	return entry
 }
 
-// flushAndClose closes the log queue and waits for the worker to finish processing remaining entries.
-func (cl *CompositeLogger) flushAndClose() {
-	close(cl.ch)
-	cl.wg.Wait()
+func joinNames(parent, child string) string {
+	switch {
+	case parent == "":
+		return child
+	case child == "":
+		return parent
+	default:
+		return parent + "." + child
+	}
+}
+
+// Dropped returns the number of entries discarded so far because the main queue
+// or one of the adapter queues was full.
+func (cl *CompositeLogger) Dropped() uint64 {
+	c, _ := cl.resolve()
+	if c == nil {
+		return 0
+	}
+
+	return c.dropped()
+}
+
+// Stop closes the log queue and waits for all workers to finish processing remaining entries.
+// Calling Stop more than once is safe. Stop on a logger returned by With or Named does nothing;
+// the adapters are owned by the root logger.
+//
+// Usage:
+//
+//	defer logger.Stop()
+func (cl *CompositeLogger) Stop() {
+	if cl == nil || cl.derived || cl.core == nil {
+		return
+	}
+
+	cl.core.stop()
+}
+
+// Trace asynchronously logs a message with the TRACE level.
+func (cl *CompositeLogger) Trace(msg string, ctx map[string]interface{}) {
+	cl.log(TraceLevel, msg, ctx)
+}
+
+// Debug asynchronously logs a message with the DEBUG level.
+func (cl *CompositeLogger) Debug(msg string, ctx map[string]interface{}) {
+	cl.log(DebugLevel, msg, ctx)
+}
+
+// Info asynchronously logs a message with the INFO level.
+func (cl *CompositeLogger) Info(msg string, ctx map[string]interface{}) {
+	cl.log(InfoLevel, msg, ctx)
+}
+
+// Warn asynchronously logs a message with the WARNING level.
+func (cl *CompositeLogger) Warn(msg string, ctx map[string]interface{}) {
+	cl.log(WarningLevel, msg, ctx)
+}
+
+// Error captures a stack trace and asynchronously logs a message with the ERROR level.
+func (cl *CompositeLogger) Error(msg string, ctx map[string]interface{}) {
+	cl.log(ErrorLevel, msg, ctx)
+}
+
+// Fatal captures a stack trace and asynchronously logs a message with the FATAL level.
+// With Options.ExitOnFatal it waits for all adapters to deliver the entry and then terminates the process.
+func (cl *CompositeLogger) Fatal(msg string, ctx map[string]interface{}) {
+	cl.log(FatalLevel, msg, ctx)
+}
+
+// TraceFields asynchronously logs a message with the TRACE level and typed fields.
+func (cl *CompositeLogger) TraceFields(msg string, fields ...Field) {
+	cl.logFields(TraceLevel, msg, fields)
+}
+
+// DebugFields asynchronously logs a message with the DEBUG level and typed fields.
+func (cl *CompositeLogger) DebugFields(msg string, fields ...Field) {
+	cl.logFields(DebugLevel, msg, fields)
+}
+
+// InfoFields asynchronously logs a message with the INFO level and typed fields.
+// Unlike Info it does not build a map for adapters implementing AttrLogger.
+//
+// Usage:
+//
+//	logger.InfoFields("request served", composite_logger.F.String("path", path), composite_logger.F.Duration("took", took))
+func (cl *CompositeLogger) InfoFields(msg string, fields ...Field) {
+	cl.logFields(InfoLevel, msg, fields)
+}
+
+// WarnFields asynchronously logs a message with the WARNING level and typed fields.
+func (cl *CompositeLogger) WarnFields(msg string, fields ...Field) {
+	cl.logFields(WarningLevel, msg, fields)
+}
+
+// ErrorFields captures a stack trace and asynchronously logs a message with the ERROR level and typed fields.
+// The stack trace carried by an F.Err field is preferred over the current one.
+func (cl *CompositeLogger) ErrorFields(msg string, fields ...Field) {
+	cl.logFields(ErrorLevel, msg, fields)
+}
+
+// FatalFields captures a stack trace and asynchronously logs a message with the FATAL level and typed fields.
+// With Options.ExitOnFatal it waits for all adapters to deliver the entry and then terminates the process.
+func (cl *CompositeLogger) FatalFields(msg string, fields ...Field) {
+	cl.logFields(FatalLevel, msg, fields)
+}
+
+// Recover is a helper to be used in defer statements to catch and log panics as FATAL errors.
+//
+// Usage:
+//
+//	defer logger.Recover(map[string]interface{}{"handler": "user_create"})
+func (cl *CompositeLogger) Recover(ctx map[string]interface{}) {
+	if r := recover(); r != nil {
+		cl.logPanic(r, ctx)
+	}
+}
+
+func (cl *CompositeLogger) logPanic(r interface{}, ctx map[string]interface{}) {
+	cl.Fatal("Panic recovered", map[string]interface{}{
+		"panic": r,
+		"ctx":   ctx,
+	})
+}
+
+// With returns a logger that adds the given fields to every entry logged through the global logger.
+// The returned logger follows re-initialization of the global logger.
+//
+// Usage:
+//
+//	log := composite_logger.With(map[string]interface{}{"component": "billing"})
+func With(fields map[string]interface{}) *CompositeLogger {
+	return (&CompositeLogger{derived: true}).With(fields)
+}
+
+// Named returns a logger whose entries, logged through the global logger, carry the given name.
+// The returned logger follows re-initialization of the global logger.
+//
+// Usage:
+//
+//	log := composite_logger.Named("billing")
+func Named(name string) *CompositeLogger {
+	return (&CompositeLogger{derived: true}).Named(name)
+}
+
+// Dropped returns the number of entries discarded so far by the global logger.
+func Dropped() uint64 {
+	return Default().Dropped()
 }
 
 // Stop gracefully shuts down the global logger, ensuring all queued logs are processed.
@@ -93,28 +463,36 @@ func (cl *CompositeLogger) flushAndClose() {
 func Stop() {
 	mu.Lock()
 	defer mu.Unlock()
-	if instance != nil {
-		instance.flushAndClose()
-		instance = nil
+	if previous := instance.Swap(nil); previous != nil {
+		previous.Stop()
 	}
 }
 
+// Trace asynchronously logs a message with the TRACE level.
+//
+// Usage:
+//
+//	composite_logger.Trace("cache lookup", map[string]interface{}{"key": key})
+func Trace(msg string, ctx map[string]interface{}) {
+	Default().Trace(msg, ctx)
+}
+
+// Debug asynchronously logs a message with the DEBUG level.
+//
+// Usage:
+//
+//	composite_logger.Debug("request payload", map[string]interface{}{"body": body})
+func Debug(msg string, ctx map[string]interface{}) {
+	Default().Debug(msg, ctx)
+}
+
 // Info asynchronously logs a message with the INFO level.
 //
 // Usage:
 //
 //	composite_logger.Info("app started", map[string]interface{}{"env": "prod"})
 func Info(msg string, ctx map[string]interface{}) {
-	mu.Lock()
-	defer mu.Unlock()
-	if instance == nil || instance.ch == nil {
-		return
-	}
-	instance.ch <- logEntry{
-		level:   InfoLevel,
-		message: "[INFO] " + msg,
-		context: ctx,
-	}
+	Default().Info(msg, ctx)
 }
 
 // Warn asynchronously logs a message with the WARNING level.
@@ -123,16 +501,7 @@ func Info(msg string, ctx map[string]interface{}) {
 //
 //	composite_logger.Warn("high latency", map[string]interface{}{"ms": 500})
 func Warn(msg string, ctx map[string]interface{}) {
-	mu.Lock()
-	defer mu.Unlock()
-	if instance == nil || instance.ch == nil {
-		return
-	}
-	instance.ch <- logEntry{
-		level:   WarningLevel,
-		message: "[WARNING] " + msg,
-		context: ctx,
-	}
+	Default().Warn(msg, ctx)
 }
 
 // Error captures a stack trace and asynchronously logs a message with the ERROR level.
@@ -141,38 +510,56 @@ func Warn(msg string, ctx map[string]interface{}) {
 //
 //	composite_logger.Error("db connection failed", map[string]interface{}{"error": err})
 func Error(msg string, ctx map[string]interface{}) {
-	ctx = internal.BuildErrorContextWithStackTrace(ctx)
-
-	mu.Lock()
-	defer mu.Unlock()
-	if instance == nil || instance.ch == nil {
-		return
-	}
-	instance.ch <- logEntry{
-		level:   ErrorLevel,
-		message: "[ERROR] " + msg,
-		context: ctx,
-	}
+	Default().Error(msg, ctx)
 }
 
 // Fatal captures a stack trace and asynchronously logs a message with the FATAL level.
+// With Options.ExitOnFatal it waits for all adapters to deliver the entry and then terminates the process.
 //
 // Usage:
 //
 //	composite_logger.Fatal("system crashed", map[string]interface{}{"reason": "out of memory"})
 func Fatal(msg string, ctx map[string]interface{}) {
-	ctx = internal.BuildErrorContextWithStackTrace(ctx)
+	Default().Fatal(msg, ctx)
+}
 
-	mu.Lock()
-	defer mu.Unlock()
-	if instance == nil || instance.ch == nil {
-		return
-	}
-	instance.ch <- logEntry{
-		level:   FatalLevel,
-		message: "[FATAL] " + msg,
-		context: ctx,
-	}
+// TraceFields asynchronously logs a message with the TRACE level and typed fields.
+func TraceFields(msg string, fields ...Field) {
+	Default().TraceFields(msg, fields...)
+}
+
+// DebugFields asynchronously logs a message with the DEBUG level and typed fields.
+func DebugFields(msg string, fields ...Field) {
+	Default().DebugFields(msg, fields...)
+}
+
+// InfoFields asynchronously logs a message with the INFO level and typed fields.
+//
+// Usage:
+//
+//	composite_logger.InfoFields("user signed in", composite_logger.F.String("user", name), composite_logger.F.Int("attempt", n))
+func InfoFields(msg string, fields ...Field) {
+	Default().InfoFields(msg, fields...)
+}
+
+// WarnFields asynchronously logs a message with the WARNING level and typed fields.
+func WarnFields(msg string, fields ...Field) {
+	Default().WarnFields(msg, fields...)
+}
+
+// ErrorFields captures a stack trace and asynchronously logs a message with the ERROR level and typed fields.
+//
+// Usage:
+//
+//	composite_logger.ErrorFields("db connection failed", composite_logger.F.Err(err))
+func ErrorFields(msg string, fields ...Field) {
+	Default().ErrorFields(msg, fields...)
+}
+
+// FatalFields captures a stack trace and asynchronously logs a message with the FATAL level and typed fields.
+// With Options.ExitOnFatal it waits for all adapters to deliver the entry and then terminates the process.
+func FatalFields(msg string, fields ...Field) {
+	Default().FatalFields(msg, fields...)
 }
 
 // Recover is a helper function to be used in defer statements to catch and log panics as FATAL errors.
@@ -182,9 +569,6 @@ func Fatal(msg string, ctx map[string]interface{}) {
 //	defer composite_logger.Recover(map[string]interface{}{"handler": "user_create"})
 func Recover(ctx map[string]interface{}) {
 	if r := recover(); r != nil {
-		Fatal("Panic recovered", map[string]interface{}{
-			"panic": r,
-			"ctx":   ctx,
-		})
+		Default().logPanic(r, ctx)
 	}
 }
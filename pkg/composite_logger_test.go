@@ -70,7 +70,7 @@ func (f *fakeLogger) Fatal(message string, context map[string]interface{}) {
 	f.fatalCalls = append(f.fatalCalls, logCall{message: message, context: context})
 }
 
-func TestInfo_FanOutAndPrefix(t *testing.T) {
+func TestInfo_FanOutWithoutPrefix(t *testing.T) {
 	l1 := &fakeLogger{}
 	l2 := &fakeLogger{}
 	Init(testSetting{l1}, testSetting{l2})
@@ -81,12 +81,12 @@ func TestInfo_FanOutAndPrefix(t *testing.T) {
 
 	require.Len(t, l1.infoCalls, 1)
 	require.Len(t, l2.infoCalls, 1)
-	assert.Equal(t, "[INFO] process started", l1.infoCalls[0].message)
-	assert.Equal(t, "[INFO] process started", l2.infoCalls[0].message)
+	assert.Equal(t, "process started", l1.infoCalls[0].message)
+	assert.Equal(t, "process started", l2.infoCalls[0].message)
 	assert.Equal(t, "abc-123", l1.infoCalls[0].context["requestId"])
 }
 
-func TestWarn_FanOutAndPrefix(t *testing.T) {
+func TestWarn_FanOutWithoutPrefix(t *testing.T) {
 	l1 := &fakeLogger{}
 	l2 := &fakeLogger{}
 	Init(testSetting{l1}, testSetting{l2})
@@ -97,8 +97,8 @@ func TestWarn_FanOutAndPrefix(t *testing.T) {
 
 	require.Len(t, l1.warnCalls, 1)
 	require.Len(t, l2.warnCalls, 1)
-	assert.Equal(t, "[WARNING] slow response", l1.warnCalls[0].message)
-	assert.Equal(t, "[WARNING] slow response", l2.warnCalls[0].message)
+	assert.Equal(t, "slow response", l1.warnCalls[0].message)
+	assert.Equal(t, "slow response", l2.warnCalls[0].message)
 	assert.Equal(t, "poll", l2.warnCalls[0].context["task"])
 }
 
@@ -113,16 +113,17 @@ func TestError_AddsFallbackStackTraceAndDoesNotMutateInputContext(t *testing.T)
 
 	require.Len(t, l1.errorCalls, 1)
 	require.Len(t, l2.errorCalls, 1)
-	assert.Equal(t, "[ERROR] failed", l1.errorCalls[0].message)
+	assert.Equal(t, "failed", l1.errorCalls[0].message)
 	assert.Equal(t, "poll", l1.errorCalls[0].context["taskType"])
 	assert.Contains(t, l1.errorCalls[0].context, "stackTrace")
 	assert.NotEmpty(t, l1.errorCalls[0].context["stackTrace"])
+	assert.NotContains(t, l1.errorCalls[0].context["stackTrace"], "composite_logger/pkg.", "library frames are cleaned")
 
 	_, hasStackInOriginal := inputCtx["stackTrace"]
 	assert.False(t, hasStackInOriginal, "original context must not be mutated")
 }
 
-func TestFatal_FanOutAndPrefix(t *testing.T) {
+func TestFatal_FanOutWithoutPrefix(t *testing.T) {
 	l1 := &fakeLogger{}
 	l2 := &fakeLogger{}
 	Init(testSetting{l1}, testSetting{l2})
@@ -133,7 +134,7 @@ func TestFatal_FanOutAndPrefix(t *testing.T) {
 
 	require.Len(t, l1.fatalCalls, 1)
 	require.Len(t, l2.fatalCalls, 1)
-	assert.Equal(t, "[FATAL] critical failure", l1.fatalCalls[0].message)
+	assert.Equal(t, "critical failure", l1.fatalCalls[0].message)
 	assert.Equal(t, "scheduler", l1.fatalCalls[0].context["service"])
 	assert.Contains(t, l1.fatalCalls[0].context, "stackTrace")
 }
@@ -149,7 +150,7 @@ func TestRecover_LogsPanicAsFatal(t *testing.T) {
 	Stop()
 
 	require.Len(t, l.fatalCalls, 1)
-	assert.Equal(t, "[FATAL] Panic recovered", l.fatalCalls[0].message)
+	assert.Equal(t, "Panic recovered", l.fatalCalls[0].message)
 	assert.Equal(t, "something went wrong", l.fatalCalls[0].context["panic"])
 	assert.Equal(t, "test", l.fatalCalls[0].context["ctx"].(map[string]interface{})["component"])
 	assert.Contains(t, l.fatalCalls[0].context, "stackTrace")
@@ -206,7 +207,7 @@ func TestRecover_NilContext(t *testing.T) {
 	Stop()
 
 	require.Len(t, l.fatalCalls, 1)
-	assert.Equal(t, "[FATAL] Panic recovered", l.fatalCalls[0].message)
+	assert.Equal(t, "Panic recovered", l.fatalCalls[0].message)
 }
 
 func TestError_WorksWithNilContext(t *testing.T) {
@@ -217,7 +218,7 @@ func TestError_WorksWithNilContext(t *testing.T) {
 	Stop()
 
 	require.Len(t, l.errorCalls, 1)
-	assert.Equal(t, "[ERROR] failed", l.errorCalls[0].message)
+	assert.Equal(t, "failed", l.errorCalls[0].message)
 	assert.Contains(t, l.errorCalls[0].context, "stackTrace")
 }
 
@@ -233,9 +234,9 @@ func TestInit_Reinitialization(t *testing.T) {
 	Stop()
 
 	assert.Len(t, l1.infoCalls, 1)
-	assert.Equal(t, "[INFO] first", l1.infoCalls[0].message)
+	assert.Equal(t, "first", l1.infoCalls[0].message)
 	assert.Len(t, l2.infoCalls, 1)
-	assert.Equal(t, "[INFO] second", l2.infoCalls[0].message)
+	assert.Equal(t, "second", l2.infoCalls[0].message)
 }
 
 func TestLogging_BeforeInitOrAfterStop(t *testing.T) {
@@ -284,3 +285,217 @@ func TestBuildErrorContextWithStackTrace_DoesNotOverrideExistingStack(t *testing
 
 	assert.Equal(t, "precomputed-stack", result["stackTrace"])
 }
+
+type panickingSetting struct{}
+
+func (panickingSetting) InitLogger() ports.Logger {
+	panic("boom")
+}
+
+func (panickingSetting) IsEnabled() bool {
+	return true
+}
+
+func TestNew_IndependentInstances(t *testing.T) {
+	l1 := &fakeLogger{}
+	l2 := &fakeLogger{}
+
+	first, err := New(testSetting{l1})
+	require.NoError(t, err)
+	second, err := New(testSetting{l2})
+	require.NoError(t, err)
+
+	first.Info("to first", nil)
+	second.Warn("to second", nil)
+	first.Stop()
+	second.Stop()
+
+	require.Len(t, l1.infoCalls, 1)
+	assert.Empty(t, l1.warnCalls)
+	require.Len(t, l2.warnCalls, 1)
+	assert.Empty(t, l2.infoCalls)
+}
+
+func TestNew_ReturnsErrorWhenAdapterFailsToInitialize(t *testing.T) {
+	cl, err := New(panickingSetting{})
+
+	assert.Nil(t, cl)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "boom")
+}
+
+func TestCompositeLogger_StopIsIdempotentAndDropsLaterEntries(t *testing.T) {
+	l := &fakeLogger{}
+	cl, err := New(testSetting{l})
+	require.NoError(t, err)
+
+	cl.Stop()
+	assert.NotPanics(t, func() {
+		cl.Stop()
+		cl.Info("after stop", nil)
+	})
+	assert.Empty(t, l.infoCalls)
+}
+
+func TestCompositeLogger_NilReceiverIsNoop(t *testing.T) {
+	var cl *CompositeLogger
+
+	assert.NotPanics(t, func() {
+		cl.Info("noop", nil)
+		cl.Error("noop", nil)
+		cl.Stop()
+	})
+}
+
+func TestSetDefault_RoutesPackageFunctions(t *testing.T) {
+	l := &fakeLogger{}
+	cl, err := New(testSetting{l})
+	require.NoError(t, err)
+
+	SetDefault(cl)
+	assert.Same(t, cl, Default())
+	Info("via package", nil)
+	SetDefault(nil)
+	cl.Stop()
+
+	require.Len(t, l.infoCalls, 1)
+	assert.Equal(t, "via package", l.infoCalls[0].message)
+}
+
+func TestInit_PanicsWhenAdapterFailsToInitialize(t *testing.T) {
+	assert.Panics(t, func() {
+		Init(panickingSetting{})
+	})
+	Stop()
+}
+
+type verboseFakeLogger struct {
+	fakeLogger
+	debugCalls []logCall
+	traceCalls []logCall
+}
+
+func (f *verboseFakeLogger) Debug(message string, context map[string]interface{}) {
+	f.debugCalls = append(f.debugCalls, logCall{message: message, context: context})
+}
+
+func (f *verboseFakeLogger) Trace(message string, context map[string]interface{}) {
+	f.traceCalls = append(f.traceCalls, logCall{message: message, context: context})
+}
+
+func TestDebugAndTrace_ReachOnlyVerboseAdapters(t *testing.T) {
+	legacy := &fakeLogger{}
+	verbose := &verboseFakeLogger{}
+	Init(testSetting{legacy}, testSetting{verbose})
+
+	Debug("cache miss", map[string]interface{}{"key": "user:1"})
+	Trace("loop step", nil)
+	Stop()
+
+	assert.Empty(t, legacy.infoCalls)
+	require.Len(t, verbose.debugCalls, 1)
+	require.Len(t, verbose.traceCalls, 1)
+	assert.Equal(t, "cache miss", verbose.debugCalls[0].message)
+	assert.Equal(t, "user:1", verbose.debugCalls[0].context["key"])
+	assert.Equal(t, "loop step", verbose.traceCalls[0].message)
+}
+
+func TestMessageDecorator_PerAdapter(t *testing.T) {
+	plain := &fakeLogger{}
+	decorated := &fakeLogger{}
+	cl, err := New(
+		testSetting{plain},
+		AdapterSetting{Setting: testSetting{decorated}, MessageDecorator: LevelPrefixDecorator},
+	)
+	require.NoError(t, err)
+
+	cl.Warn("disk almost full", nil)
+	cl.Stop()
+
+	require.Len(t, plain.warnCalls, 1)
+	require.Len(t, decorated.warnCalls, 1)
+	assert.Equal(t, "disk almost full", plain.warnCalls[0].message)
+	assert.Equal(t, "[WARNING] disk almost full", decorated.warnCalls[0].message)
+}
+
+func TestOptions_LegacyLevelPrefix(t *testing.T) {
+	legacy := &fakeLogger{}
+	custom := &fakeLogger{}
+	cl, err := NewWithOptions(Options{LegacyLevelPrefix: true},
+		testSetting{legacy},
+		AdapterSetting{Setting: testSetting{custom}, MessageDecorator: func(entry Entry) string {
+			return entry.Level.String() + ": " + entry.Message
+		}},
+	)
+	require.NoError(t, err)
+
+	cl.Info("started", nil)
+	cl.Stop()
+
+	require.Len(t, legacy.infoCalls, 1)
+	require.Len(t, custom.infoCalls, 1)
+	assert.Equal(t, "[INFO] started", legacy.infoCalls[0].message)
+	assert.Equal(t, "info: started", custom.infoCalls[0].message)
+}
+
+func TestWith_BindsFieldsOnInstance(t *testing.T) {
+	l := &entryOnlyLogger{}
+	cl, err := New(testSetting{l})
+	require.NoError(t, err)
+
+	billing := cl.With(map[string]interface{}{"component": "billing", "region": "eu"})
+	billing.Info("charged", map[string]interface{}{"region": "us"})
+	cl.Info("root", nil)
+	billing.Stop() // derived loggers do not own the adapters
+	billing.Info("still running", nil)
+	cl.Stop()
+
+	require.Len(t, l.entries, 3)
+	assert.Equal(t, "billing", l.entries[0].Fields["component"])
+	assert.Equal(t, "us", l.entries[0].Fields["region"], "call fields win over bound fields")
+	assert.NotContains(t, l.entries[1].Fields, "component")
+	assert.Equal(t, "still running", l.entries[2].Message)
+}
+
+func TestNamed_BuildsHierarchicalNames(t *testing.T) {
+	l := &entryOnlyLogger{}
+	cl, err := New(testSetting{l})
+	require.NoError(t, err)
+
+	invoices := cl.Named("billing").With(map[string]interface{}{"shard": 2}).Named("invoices")
+	invoices.Error("failed", nil)
+	cl.Stop()
+
+	assert.Equal(t, "billing.invoices", invoices.Name())
+	require.Len(t, l.entries, 1)
+	assert.Equal(t, "billing.invoices", l.entries[0].Logger)
+	assert.Equal(t, "billing.invoices", l.entries[0].Fields[LoggerKey])
+	assert.Equal(t, 2, l.entries[0].Fields["shard"])
+	assert.Contains(t, l.entries[0].Fields, "stackTrace")
+}
+
+func TestWith_GlobalFollowsReinitialization(t *testing.T) {
+	log := Named("worker").With(map[string]interface{}{"component": "scheduler"})
+
+	l1 := &fakeLogger{}
+	Init(testSetting{l1})
+	log.Warn("first", nil)
+
+	l2 := &fakeLogger{}
+	Init(testSetting{l2})
+	log.Warn("second", nil)
+	Stop()
+
+	assert.NotPanics(t, func() { log.Warn("after stop", nil) })
+	require.Len(t, l1.warnCalls, 1)
+	require.Len(t, l2.warnCalls, 1)
+	assert.Equal(t, "scheduler", l2.warnCalls[0].context["component"])
+	assert.Equal(t, "worker", l2.warnCalls[0].context[LoggerKey])
+}
+
+func TestWith_DoesNotMutateParentFields(t *testing.T) {
+	parent := With(map[string]interface{}{"a": 1})
+	_ = parent.With(map[string]interface{}{"b": 2})
+
+	assert.Equal(t, map[string]interface{}{"a": 1}, parent.fields)
+}
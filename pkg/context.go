@@ -0,0 +1,134 @@
+package composite_logger
+
+import (
+	"context"
+
+	"github.com/Consolushka/golang.composite_logger/internal"
+)
+
+// Field keys used by the context helpers.
+const (
+	RequestIDKey = "request_id"
+	UserIDKey    = "user_id"
+	TenantIDKey  = "tenant_id"
+)
+
+type contextFieldsKey struct{}
+
+// WithFields returns a copy of ctx carrying the given fields in addition to those already attached to it.
+// The fields are merged into every entry logged with the returned context; fields passed to the log call win on conflicts.
+//
+// Usage:
+//
+//	ctx = composite_logger.WithFields(ctx, map[string]interface{}{"order_id": id})
+func WithFields(ctx context.Context, fields map[string]interface{}) context.Context {
+	if len(fields) == 0 {
+		return ctx
+	}
+
+	return context.WithValue(ctx, contextFieldsKey{}, internal.MergeContext(FieldsFromContext(ctx), fields))
+}
+
+// WithRequestID attaches a request ID to ctx under the "request_id" key.
+//
+// Usage:
+//
+//	ctx = composite_logger.WithRequestID(r.Context(), r.Header.Get("X-Request-ID"))
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return WithFields(ctx, map[string]interface{}{RequestIDKey: requestID})
+}
+
+// WithUserID attaches a user ID to ctx under the "user_id" key.
+func WithUserID(ctx context.Context, userID interface{}) context.Context {
+	return WithFields(ctx, map[string]interface{}{UserIDKey: userID})
+}
+
+// WithTenantID attaches a tenant ID to ctx under the "tenant_id" key.
+func WithTenantID(ctx context.Context, tenantID interface{}) context.Context {
+	return WithFields(ctx, map[string]interface{}{TenantIDKey: tenantID})
+}
+
+// FieldsFromContext returns the fields attached to ctx, or nil if there are none.
+// The returned map must not be modified.
+func FieldsFromContext(ctx context.Context) map[string]interface{} {
+	if ctx == nil {
+		return nil
+	}
+
+	fields, _ := ctx.Value(contextFieldsKey{}).(map[string]interface{})
+	return fields
+}
+
+// contextFields merges the fields attached to ctx with the fields of a log call.
+func contextFields(ctx context.Context, fields map[string]interface{}) map[string]interface{} {
+	attached := FieldsFromContext(ctx)
+	if len(attached) == 0 {
+		return fields
+	}
+
+	return internal.MergeContext(attached, fields)
+}
+
+// TraceContext logs a message with the TRACE level and the fields attached to ctx.
+func (cl *CompositeLogger) TraceContext(ctx context.Context, msg string, fields map[string]interface{}) {
+	cl.log(TraceLevel, msg, contextFields(ctx, fields))
+}
+
+// DebugContext logs a message with the DEBUG level and the fields attached to ctx.
+func (cl *CompositeLogger) DebugContext(ctx context.Context, msg string, fields map[string]interface{}) {
+	cl.log(DebugLevel, msg, contextFields(ctx, fields))
+}
+
+// InfoContext logs a message with the INFO level and the fields attached to ctx.
+func (cl *CompositeLogger) InfoContext(ctx context.Context, msg string, fields map[string]interface{}) {
+	cl.log(InfoLevel, msg, contextFields(ctx, fields))
+}
+
+// WarnContext logs a message with the WARNING level and the fields attached to ctx.
+func (cl *CompositeLogger) WarnContext(ctx context.Context, msg string, fields map[string]interface{}) {
+	cl.log(WarningLevel, msg, contextFields(ctx, fields))
+}
+
+// ErrorContext logs a message with the ERROR level, a stack trace and the fields attached to ctx.
+func (cl *CompositeLogger) ErrorContext(ctx context.Context, msg string, fields map[string]interface{}) {
+	cl.log(ErrorLevel, msg, contextFields(ctx, fields))
+}
+
+// FatalContext logs a message with the FATAL level, a stack trace and the fields attached to ctx.
+func (cl *CompositeLogger) FatalContext(ctx context.Context, msg string, fields map[string]interface{}) {
+	cl.log(FatalLevel, msg, contextFields(ctx, fields))
+}
+
+// TraceContext logs a message with the TRACE level and the fields attached to ctx through the global logger.
+func TraceContext(ctx context.Context, msg string, fields map[string]interface{}) {
+	Default().TraceContext(ctx, msg, fields)
+}
+
+// DebugContext logs a message with the DEBUG level and the fields attached to ctx through the global logger.
+func DebugContext(ctx context.Context, msg string, fields map[string]interface{}) {
+	Default().DebugContext(ctx, msg, fields)
+}
+
+// InfoContext logs a message with the INFO level and the fields attached to ctx through the global logger.
+//
+// Usage:
+//
+//	composite_logger.InfoContext(ctx, "order created", map[string]interface{}{"order_id": id})
+func InfoContext(ctx context.Context, msg string, fields map[string]interface{}) {
+	Default().InfoContext(ctx, msg, fields)
+}
+
+// WarnContext logs a message with the WARNING level and the fields attached to ctx through the global logger.
+func WarnContext(ctx context.Context, msg string, fields map[string]interface{}) {
+	Default().WarnContext(ctx, msg, fields)
+}
+
+// ErrorContext logs a message with the ERROR level, a stack trace and the fields attached to ctx through the global logger.
+func ErrorContext(ctx context.Context, msg string, fields map[string]interface{}) {
+	Default().ErrorContext(ctx, msg, fields)
+}
+
+// FatalContext logs a message with the FATAL level, a stack trace and the fields attached to ctx through the global logger.
+func FatalContext(ctx context.Context, msg string, fields map[string]interface{}) {
+	Default().FatalContext(ctx, msg, fields)
+}
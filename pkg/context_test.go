@@ -0,0 +1,67 @@
+package composite_logger
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContextHelpers_AccumulateFields(t *testing.T) {
+	ctx := WithRequestID(context.Background(), "req-1")
+	ctx = WithUserID(ctx, 42)
+	ctx = WithTenantID(ctx, "acme")
+	ctx = WithFields(ctx, map[string]interface{}{"order_id": "o-7"})
+
+	assert.Equal(t, map[string]interface{}{
+		RequestIDKey: "req-1",
+		UserIDKey:    42,
+		TenantIDKey:  "acme",
+		"order_id":   "o-7",
+	}, FieldsFromContext(ctx))
+}
+
+func TestWithFields_DoesNotAffectParentContext(t *testing.T) {
+	parent := WithRequestID(context.Background(), "req-1")
+	_ = WithUserID(parent, 42)
+
+	assert.NotContains(t, FieldsFromContext(parent), UserIDKey)
+}
+
+func TestFieldsFromContext_Empty(t *testing.T) {
+	assert.Nil(t, FieldsFromContext(context.Background()))
+	//nolint:staticcheck // a nil context must be tolerated
+	assert.Nil(t, FieldsFromContext(nil))
+}
+
+func TestInfoContext_MergesContextFields(t *testing.T) {
+	l := &fakeLogger{}
+	Init(testSetting{l})
+
+	ctx := WithRequestID(context.Background(), "req-1")
+	InfoContext(ctx, "handled", map[string]interface{}{"status": 200})
+	InfoContext(ctx, "overridden", map[string]interface{}{RequestIDKey: "explicit"})
+	Stop()
+
+	require.Len(t, l.infoCalls, 2)
+	assert.Equal(t, "req-1", l.infoCalls[0].context[RequestIDKey])
+	assert.Equal(t, 200, l.infoCalls[0].context["status"])
+	assert.Equal(t, "explicit", l.infoCalls[1].context[RequestIDKey])
+}
+
+func TestErrorContext_AddsStackTraceAndContextFields(t *testing.T) {
+	l := &fakeLogger{}
+	cl, err := New(testSetting{l})
+	require.NoError(t, err)
+
+	fields := map[string]interface{}{"op": "charge"}
+	cl.ErrorContext(WithTenantID(context.Background(), "acme"), "failed", fields)
+	cl.Stop()
+
+	require.Len(t, l.errorCalls, 1)
+	assert.Equal(t, "acme", l.errorCalls[0].context[TenantIDKey])
+	assert.Equal(t, "charge", l.errorCalls[0].context["op"])
+	assert.Contains(t, l.errorCalls[0].context, "stackTrace")
+	assert.NotContains(t, fields, TenantIDKey, "caller fields must not be mutated")
+}
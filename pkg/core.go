@@ -0,0 +1,442 @@
+package composite_logger
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Consolushka/golang.composite_logger/pkg/ports"
+)
+
+// core owns the adapters, queues and workers shared by a root CompositeLogger and all loggers derived from it.
+type core struct {
+	opts     Options
+	redactor *redactor
+	sampler  *sampler
+	router   *router
+	sinks    []*sink
+	queue    *queue
+	wg       sync.WaitGroup
+	done     chan struct{}
+
+	// mu guards closed and prevents sending to the queue once it has been closed.
+	mu     sync.RWMutex
+	closed bool
+	// aborted is closed when the Shutdown deadline expires; queues stop waiting for room then.
+	aborted   chan struct{}
+	abortOnce sync.Once
+
+	// reportedDrops is the number of dropped entries already announced by reportDropped.
+	reportedDrops atomic.Uint64
+	// sequence is the number of the last accepted entry.
+	sequence atomic.Uint64
+}
+
+// newCore initializes the enabled adapters and starts the background workers.
+// Spools opened before a failure are closed again, so their directories can be used by the next attempt.
+func newCore(opts Options, settings ...LoggerSetting) (_ *core, err error) {
+	sinks := make([]*sink, 0, len(settings))
+	defer func() {
+		if err != nil {
+			closeSpools(sinks)
+		}
+	}()
+	names := make(map[string]int, len(settings))
+	known := make(map[string]bool, len(settings))
+	for _, s := range settings {
+		if s == nil {
+			continue
+		}
+		if !s.IsEnabled() {
+			known[opts.adapterSetting(s).name()] = true
+			continue
+		}
+
+		as := opts.adapterSetting(s)
+		name, err := uniqueName(names, as)
+		if err != nil {
+			return nil, err
+		}
+
+		l, err := initLogger(s)
+		if err != nil {
+			return nil, err
+		}
+		known[name] = true
+		adapterSink := newSink(name, l, as, opts)
+		if as.Spool != nil {
+			if err := adapterSink.openSpool(as.Spool); err != nil {
+				return nil, fmt.Errorf("composite_logger: spool of adapter %q: %w", name, err)
+			}
+		}
+		sinks = append(sinks, adapterSink)
+	}
+
+	r, err := newRouter(opts.Routes, sinks, known, opts.errorHandler())
+	if err != nil {
+		return nil, err
+	}
+
+	c := &core{
+		opts:     opts,
+		redactor: newRedactor(opts.Redaction),
+		sampler:  newSampler(opts.Sampling),
+		router:   r,
+		sinks:    sinks,
+		queue:    newQueue(opts.QueueSize, opts.overflowPolicy(), opts.BlockTimeout, (*delivery).finish),
+		done:     make(chan struct{}),
+		aborted:  make(chan struct{}),
+	}
+	c.queue.abort = c.aborted
+	for _, s := range sinks {
+		s.sequence = &c.sequence
+		s.queue.abort = c.aborted
+		s.input().abort = c.aborted
+		if a, ok := s.adapter.(abortable); ok {
+			a.abortOn(c.aborted)
+		}
+	}
+
+	c.wg.Add(1 + len(sinks))
+	for _, s := range sinks {
+		go s.run(&c.wg)
+		if s.intake != nil {
+			c.wg.Add(1)
+			go s.runSpool(&c.wg)
+		}
+	}
+	go c.listenAndBroadcast()
+
+	if interval := opts.dropReportInterval(); interval > 0 {
+		c.wg.Add(1)
+		go c.reportEvery(interval)
+	}
+
+	return c, nil
+}
+
+// closeSpools closes the spools of sinks whose workers were never started.
+func closeSpools(sinks []*sink) {
+	for _, s := range sinks {
+		if s.spool != nil {
+			_ = s.spool.close()
+		}
+	}
+}
+
+// uniqueName returns the adapter name, numbering derived names that are already taken, e.g. "console#2".
+// Explicitly configured names must be unique.
+func uniqueName(names map[string]int, as AdapterSetting) (string, error) {
+	name := as.name()
+	names[name]++
+	if names[name] == 1 {
+		return name, nil
+	}
+	if as.Name != "" {
+		return "", fmt.Errorf("composite_logger: duplicate adapter name %q", name)
+	}
+
+	return fmt.Sprintf("%s#%d", name, names[name]), nil
+}
+
+// initLogger converts a panic raised by an adapter setting during initialization into an error.
+func initLogger(s LoggerSetting) (l ports.Logger, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("composite_logger: failed to initialize %T: %v", s, r)
+		}
+	}()
+
+	return s.InitLogger(), nil
+}
+
+// listenAndBroadcast is a background worker that processes the log queue
+// and hands entries over to the queues of all registered adapters. Flush barriers are registered
+// with every adapter without waiting, so a hung adapter cannot stall the others.
+// Once the log queue is closed, it closes the adapter queues so their workers can drain and exit.
+func (c *core) listenAndBroadcast() {
+	defer c.wg.Done()
+	for entry := range c.queue.ch {
+		if entry.barrier != nil {
+			for _, s := range c.sinks {
+				s.addBarrier(entry)
+			}
+			continue
+		}
+
+		if c.sampler != nil && !c.sampler.allow(entry, time.Now()) {
+			c.completeAll(entry, ErrEntrySuppressed)
+			continue
+		}
+
+		if c.redactor != nil || len(c.opts.Processors) > 0 {
+			var keep bool
+			if entry, keep = c.process(entry); !keep {
+				c.completeAll(entry, ErrEntryFiltered)
+				continue
+			}
+		}
+
+		if entry.internal || c.router == nil {
+			for _, s := range c.sinks {
+				s.offer(entry)
+			}
+			continue
+		}
+
+		route := c.router.route(entry)
+		for _, s := range route.skipped {
+			entry.delivery.complete(s.name, ErrEntryNotRouted)
+		}
+		for _, s := range route.targets {
+			s.offer(entry)
+		}
+	}
+
+	// Entries sampled out after the last periodic report are summarized before the adapters shut down.
+	if c.sampler != nil {
+		if summary, ok := c.sampler.summary("sampling"); ok {
+			c.broadcastSummary(summary)
+		}
+	}
+
+	for _, s := range c.sinks {
+		close(s.input().ch)
+	}
+}
+
+// completeAll records the same result for every adapter of an entry that is not fanned out.
+func (c *core) completeAll(entry Entry, err error) {
+	for _, s := range c.sinks {
+		entry.delivery.complete(s.name, err)
+	}
+}
+
+// process redacts the entry and runs Options.Processors on its fields as a map, reporting a panicking processor to the error handler.
+func (c *core) process(entry Entry) (Entry, bool) {
+	entry = entry.flatten()
+	if c.redactor != nil {
+		entry = c.redactor.redact(entry)
+	}
+
+	processed, keep, err := process(c.opts.Processors, entry)
+	if err != nil {
+		reportError(c.opts.errorHandler(), &AdapterError{Entry: entry, Err: err})
+	}
+
+	return processed, keep
+}
+
+// enqueue numbers the entry and puts it into the log queue according to the overflow policy,
+// unless the core is already stopped. It reports whether the core was still running.
+func (c *core) enqueue(entry Entry) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.closed {
+		entry.delivery.finish()
+		return false
+	}
+	entry.Sequence = c.sequence.Add(1)
+	c.queue.push(entry)
+
+	return true
+}
+
+// enqueueContext is enqueue for callers that stop waiting once ctx is done:
+// if the log queue is still full then, the entry is dropped.
+func (c *core) enqueueContext(ctx context.Context, entry Entry) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.closed {
+		entry.delivery.finish()
+		return false
+	}
+	entry.Sequence = c.sequence.Add(1)
+	c.queue.pushUntil(entry, ctx.Done())
+
+	return true
+}
+
+// tryEnqueue numbers the entry and puts it into the log queue if it has room, unless the core is already stopped.
+// It reports whether the entry was queued.
+func (c *core) tryEnqueue(entry Entry) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.closed {
+		return false
+	}
+	entry.Sequence = c.sequence.Add(1)
+
+	return c.queue.tryPush(entry)
+}
+
+// fatal delivers a fatal entry and terminates the process when Options.ExitOnFatal is set.
+// It waits until every adapter has handled the entry, then flushes and closes the adapters so entries
+// they buffer are written before Options.ExitFunc is called. Options.FatalTimeout bounds all of it,
+// including the wait for room in a full log queue.
+func (c *core) fatal(entry Entry) {
+	if !c.opts.ExitOnFatal {
+		c.enqueue(entry)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.opts.fatalTimeout())
+	defer cancel()
+
+	entry.delivery = newDelivery(len(c.sinks))
+	if c.enqueueContext(ctx, entry) {
+		select {
+		case <-entry.delivery.done:
+		case <-ctx.Done():
+		}
+		c.closeAdapters(ctx)
+	}
+
+	c.opts.exitFunc()(1)
+}
+
+// closeAdapters flushes and closes every adapter concurrently, reporting failures to the error handler.
+// Adapters still busy with earlier entries are closed as well, since the process is about to exit.
+func (c *core) closeAdapters(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, s := range c.sinks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := s.close(ctx); err != nil {
+				s.report(&AdapterError{Adapter: s.name, Err: err})
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// stop closes the log queue, waits for all workers to finish processing remaining entries and closes the adapters.
+func (c *core) stop() {
+	_, _ = c.shutdown(context.Background())
+}
+
+// close stops accepting entries and reports whether this call closed the core.
+// Once ctx is done, log calls and the dispatcher stop waiting for room in full queues and drop their entries,
+// so neither close nor the rest of the shutdown waits on a wedged adapter beyond ctx.
+func (c *core) close(ctx context.Context) bool {
+	context.AfterFunc(ctx, c.abort)
+	c.reportDropped()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return false
+	}
+	c.closed = true
+	close(c.queue.ch)
+	close(c.done)
+
+	return true
+}
+
+// abort makes full queues discard entries instead of waiting for room.
+func (c *core) abort() {
+	c.abortOnce.Do(func() { close(c.aborted) })
+}
+
+// abortable is implemented by adapters that wait between delivery attempts, such as RetrySetting,
+// so a Shutdown that gives up does not leave them waiting.
+type abortable interface {
+	// abortOn makes the adapter stop waiting once aborted is closed. It is called before the adapter is used.
+	abortOn(aborted <-chan struct{})
+}
+
+// dropped returns the number of entries discarded by the main queue and all adapter queues.
+func (c *core) dropped() uint64 {
+	total := c.queue.dropped.Load()
+	for _, s := range c.sinks {
+		total += s.dropped()
+	}
+
+	return total
+}
+
+// reportEvery periodically logs warnings about entries dropped or suppressed since the previous report.
+func (c *core) reportEvery(interval time.Duration) {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			c.report()
+		case <-c.done:
+			return
+		}
+	}
+}
+
+// report logs warnings about entries dropped or suppressed since the previous report.
+func (c *core) report() {
+	c.reportDropped()
+	c.reportSuppressed()
+}
+
+// reportSuppressed logs a summary of the entries withheld by sampling and rate limits.
+// Summaries of an adapter's own sampler or rate limiter are sent to that adapter only.
+func (c *core) reportSuppressed() {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.closed {
+		return
+	}
+
+	if c.sampler != nil {
+		if summary, ok := c.sampler.summary("sampling"); ok {
+			c.broadcastSummary(summary)
+		}
+	}
+
+	for _, s := range c.sinks {
+		for _, summary := range s.summaries() {
+			summary.Sequence = c.sequence.Add(1)
+			s.offer(summary)
+		}
+	}
+}
+
+// broadcastSummary offers a summary of the global sampler to every adapter.
+// The keys of its counts are built from messages that have not been redacted yet, so they are redacted here.
+func (c *core) broadcastSummary(summary Entry) {
+	if byKey, ok := summary.Fields["suppressed_by_key"].(map[string]interface{}); ok && c.redactor != nil {
+		summary.Fields["suppressed_by_key"] = c.redactor.redactKeys(byKey)
+	}
+	summary.Sequence = c.sequence.Add(1)
+
+	for _, s := range c.sinks {
+		s.offer(summary)
+	}
+}
+
+// reportDropped logs a warning if entries were dropped since the previous report.
+// It never waits for room in the log queue; if the queue is full, the drops are reported next time.
+func (c *core) reportDropped() {
+	total := c.dropped()
+	previous := c.reportedDrops.Swap(total)
+	if total <= previous {
+		return
+	}
+
+	queued := c.tryEnqueue(Entry{
+		Level:   WarningLevel,
+		Time:    time.Now(),
+		Message: fmt.Sprintf("%d log entries dropped", total-previous),
+		Fields: map[string]interface{}{
+			"dropped":       total - previous,
+			"total_dropped": total,
+		},
+	})
+	if !queued {
+		c.reportedDrops.CompareAndSwap(total, previous)
+	}
+}
@@ -0,0 +1,141 @@
+package composite_logger
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// slowLogger records fatal messages after a delay.
+type slowLogger struct {
+	fakeLogger
+	delay     time.Duration
+	delivered atomic.Int32
+}
+
+func (s *slowLogger) Fatal(message string, context map[string]interface{}) {
+	time.Sleep(s.delay)
+	s.delivered.Add(1)
+}
+
+func TestFatal_ExitsAfterAllAdaptersDelivered(t *testing.T) {
+	slow := &slowLogger{delay: 20 * time.Millisecond}
+	exitCode := make(chan int, 1)
+
+	cl, err := NewWithOptions(Options{
+		ExitOnFatal: true,
+		ExitFunc:    func(code int) { exitCode <- code },
+	}, testSetting{slow}, testSetting{&fakeLogger{}})
+	require.NoError(t, err)
+	defer cl.Stop()
+
+	cl.Fatal("disk corrupted", nil)
+
+	assert.Equal(t, int32(1), slow.delivered.Load(), "Fatal must return only after delivery")
+	select {
+	case code := <-exitCode:
+		assert.Equal(t, 1, code)
+	default:
+		t.Fatal("exit hook was not called")
+	}
+}
+
+func TestFatal_ExitsWhenDeadlineExpires(t *testing.T) {
+	hung := blockingLogger{release: make(chan struct{})}
+	defer close(hung.release)
+	exited := make(chan struct{}, 1)
+
+	cl, err := NewWithOptions(Options{
+		ExitOnFatal:  true,
+		FatalTimeout: 20 * time.Millisecond,
+		ExitFunc:     func(int) { exited <- struct{}{} },
+	}, testSetting{hung})
+	require.NoError(t, err)
+
+	start := time.Now()
+	cl.Fatal("unreachable adapter", nil)
+
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+	assert.Len(t, exited, 1)
+}
+
+func TestFatal_ExitsWhenQueuesAreFull(t *testing.T) {
+	hung := blockingLogger{release: make(chan struct{})}
+	defer close(hung.release)
+	exited := make(chan struct{}, 1)
+
+	cl, err := NewWithOptions(Options{
+		QueueSize:          1,
+		DropReportInterval: -1,
+		ExitOnFatal:        true,
+		FatalTimeout:       20 * time.Millisecond,
+		ExitFunc:           func(int) { exited <- struct{}{} },
+	}, AdapterSetting{Setting: testSetting{hung}, QueueSize: 1, OverflowPolicy: OverflowBlock})
+	require.NoError(t, err)
+
+	go func() {
+		for i := 0; i < 5; i++ {
+			cl.Info("burst", nil)
+		}
+	}()
+	require.Eventually(t, func() bool { return len(cl.core.queue.ch) == 1 }, time.Second, time.Millisecond)
+
+	go cl.Fatal("unreachable adapter", nil)
+
+	select {
+	case <-exited:
+	case <-time.After(time.Second):
+		t.Fatal("Fatal waited for room in the full queue beyond FatalTimeout")
+	}
+}
+
+func TestFatal_DoesNotExitByDefault(t *testing.T) {
+	l := &fakeLogger{}
+	cl, err := New(testSetting{l})
+	require.NoError(t, err)
+
+	cl.Fatal("logged only", nil)
+	cl.Stop()
+
+	require.Len(t, l.fatalCalls, 1)
+}
+
+func TestFatal_ExitsWhenLoggerIsStopped(t *testing.T) {
+	exited := make(chan struct{}, 1)
+	cl, err := NewWithOptions(Options{
+		ExitOnFatal: true,
+		ExitFunc:    func(int) { exited <- struct{}{} },
+	}, testSetting{&fakeLogger{}})
+	require.NoError(t, err)
+
+	cl.Stop()
+	cl.Fatal("after stop", nil)
+
+	assert.Len(t, exited, 1)
+}
+
+func TestFatal_FlushesAndClosesAdaptersBeforeExit(t *testing.T) {
+	l := &lifecycleLogger{}
+	var flushedBeforeExit int
+	exited := make(chan struct{}, 1)
+
+	cl, err := NewWithOptions(Options{
+		ExitOnFatal: true,
+		ExitFunc: func(int) {
+			flushedBeforeExit = l.flushed
+			exited <- struct{}{}
+		},
+	}, testSetting{l})
+	require.NoError(t, err)
+	defer cl.Stop()
+
+	cl.Fatal("disk corrupted", nil)
+
+	assert.Len(t, exited, 1)
+	assert.Equal(t, 1, flushedBeforeExit)
+	assert.Equal(t, 1, l.closed)
+	require.Len(t, l.fatalCalls, 1)
+}
@@ -0,0 +1,13 @@
+package composite_logger
+
+import "strings"
+
+// MessageDecorator renders the message text an adapter receives for an entry.
+// The level is carried as structured data in Entry, so adapters get the bare message unless a decorator is set.
+type MessageDecorator func(entry Entry) string
+
+// LevelPrefixDecorator prepends the upper-case level in brackets, e.g. "[INFO] app started".
+// It reproduces the message format used before the level became structured data.
+func LevelPrefixDecorator(entry Entry) string {
+	return "[" + strings.ToUpper(entry.Level.String()) + "] " + entry.Message
+}
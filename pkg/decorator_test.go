@@ -0,0 +1,25 @@
+package composite_logger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLevelPrefixDecorator(t *testing.T) {
+	tests := []struct {
+		level    Level
+		expected string
+	}{
+		{TraceLevel, "[TRACE] msg"},
+		{DebugLevel, "[DEBUG] msg"},
+		{InfoLevel, "[INFO] msg"},
+		{WarningLevel, "[WARNING] msg"},
+		{ErrorLevel, "[ERROR] msg"},
+		{FatalLevel, "[FATAL] msg"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, LevelPrefixDecorator(Entry{Level: tt.level, Message: "msg"}))
+	}
+}
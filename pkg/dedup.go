@@ -0,0 +1,136 @@
+package composite_logger
+
+import "time"
+
+const (
+	// defaultDedupWindow is the deduplication window when none is configured.
+	defaultDedupWindow = 10 * time.Second
+	// maxDedupGroups bounds the fingerprints tracked at once; further distinct entries pass through unchanged.
+	maxDedupGroups = 10000
+)
+
+// Field keys of entries collapsed by Dedup.
+const (
+	RepeatCountKey = "repeat_count"
+	FirstSeenKey   = "first_seen"
+	LastSeenKey    = "last_seen"
+)
+
+// Dedup collapses bursts of identical entries for one adapter. The first entry with a fingerprint is delivered
+// at once; repeats within Window of it are held back and delivered as a single entry, the most recent repeat,
+// with the number of held repeats under "repeat_count" and the times of the first entry and the last repeat
+// under "first_seen" and "last_seen". Fatal entries are never held back.
+//
+// Usage:
+//
+//	composite_logger.AdapterSetting{
+//		Setting: setting.TelegramSetting{Enabled: true, BotKey: "KEY", ChatId: 1},
+//		Dedup:   &composite_logger.Dedup{Window: time.Minute},
+//	}
+type Dedup struct {
+	// Window is how long repeats of an entry are held back (default: 10 seconds).
+	Window time.Duration
+	// IncludeStack adds the stack trace to the fingerprint, so the same message from different code paths
+	// is not collapsed. By default entries are fingerprinted by level and message.
+	IncludeStack bool
+}
+
+// repeatGroup tracks the repeats of one fingerprint within the current window.
+type repeatGroup struct {
+	firstSeen time.Time
+	last      Entry
+	repeats   int
+}
+
+// deduper implements Dedup. It is only used by the adapter's worker goroutine.
+type deduper struct {
+	window       time.Duration
+	includeStack bool
+	groups       map[string]*repeatGroup
+}
+
+func newDeduper(dedup *Dedup) *deduper {
+	if dedup == nil {
+		return nil
+	}
+
+	d := &deduper{window: dedup.Window, includeStack: dedup.IncludeStack, groups: make(map[string]*repeatGroup)}
+	if d.window <= 0 {
+		d.window = defaultDedupWindow
+	}
+
+	return d
+}
+
+func (d *deduper) fingerprint(entry Entry) string {
+	key := entry.Level.String() + "\x00" + entry.Message
+	if d.includeStack {
+		key += "\x00" + entry.Stack
+	}
+
+	return key
+}
+
+// admit reports whether the entry is delivered now; repeats are held back.
+// If the previous window of the entry's fingerprint has passed, its collapsed entry is returned
+// to be delivered first.
+func (d *deduper) admit(entry Entry) (bool, *Entry) {
+	if d == nil || entry.internal || entry.Level == FatalLevel {
+		return true, nil
+	}
+
+	key := d.fingerprint(entry)
+	group, ok := d.groups[key]
+	if ok && entry.Time.Sub(group.firstSeen) < d.window {
+		group.last = entry
+		group.repeats++
+		return false, nil
+	}
+
+	var collapsed *Entry
+	if ok && group.repeats > 0 {
+		c := group.collapse()
+		collapsed = &c
+	}
+
+	if ok || len(d.groups) < maxDedupGroups {
+		d.groups[key] = &repeatGroup{firstSeen: entry.Time}
+	}
+
+	return true, collapsed
+}
+
+// expired removes the groups whose window has passed at now, or all groups if now is zero,
+// and returns the collapsed entries of those with held-back repeats.
+func (d *deduper) expired(now time.Time) []Entry {
+	if d == nil {
+		return nil
+	}
+
+	var collapsed []Entry
+	for key, group := range d.groups {
+		if !now.IsZero() && now.Sub(group.firstSeen) < d.window {
+			continue
+		}
+
+		delete(d.groups, key)
+		if group.repeats > 0 {
+			collapsed = append(collapsed, group.collapse())
+		}
+	}
+
+	return collapsed
+}
+
+// collapse returns the most recent repeat annotated with the repeat count and times.
+func (g *repeatGroup) collapse() Entry {
+	entry := g.last.WithFields(map[string]interface{}{
+		RepeatCountKey: g.repeats,
+		FirstSeenKey:   g.firstSeen,
+		LastSeenKey:    g.last.Time,
+	})
+	entry.delivery = nil
+	entry.internal = true
+
+	return entry
+}
@@ -0,0 +1,92 @@
+package composite_logger
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeduper_CollapsesRepeatsWithinWindow(t *testing.T) {
+	d := newDeduper(&Dedup{Window: time.Minute})
+	start := time.Now()
+	entry := func(offset time.Duration) Entry {
+		return Entry{Level: ErrorLevel, Message: "db down", Time: start.Add(offset)}
+	}
+
+	admitted, collapsed := d.admit(entry(0))
+	assert.True(t, admitted)
+	assert.Nil(t, collapsed)
+	for i := 1; i <= 3; i++ {
+		admitted, _ = d.admit(entry(time.Duration(i) * time.Second))
+		assert.False(t, admitted)
+	}
+	admitted, _ = d.admit(Entry{Level: WarningLevel, Message: "db down", Time: start})
+	assert.True(t, admitted, "fingerprints include the level")
+	admitted, _ = d.admit(Entry{Level: FatalLevel, Message: "db down", Time: start})
+	assert.True(t, admitted, "fatal entries are never held back")
+
+	assert.Empty(t, d.expired(start.Add(30*time.Second)))
+
+	admitted, collapsed = d.admit(entry(time.Minute))
+	assert.True(t, admitted, "a new window starts with the next entry")
+	require.NotNil(t, collapsed)
+	assert.Equal(t, 3, collapsed.Fields[RepeatCountKey])
+	assert.Equal(t, start, collapsed.Fields[FirstSeenKey])
+	assert.Equal(t, start.Add(3*time.Second), collapsed.Fields[LastSeenKey])
+	assert.True(t, collapsed.internal)
+}
+
+func TestDeduper_ExpiredReturnsHeldRepeats(t *testing.T) {
+	d := newDeduper(&Dedup{Window: time.Minute})
+	start := time.Now()
+
+	d.admit(Entry{Level: InfoLevel, Message: "retrying", Time: start})
+	d.admit(Entry{Level: InfoLevel, Message: "retrying", Time: start.Add(time.Second)})
+	d.admit(Entry{Level: InfoLevel, Message: "connected", Time: start})
+
+	collapsed := d.expired(start.Add(time.Minute))
+	require.Len(t, collapsed, 1)
+	assert.Equal(t, "retrying", collapsed[0].Message)
+	assert.Equal(t, 1, collapsed[0].Fields[RepeatCountKey])
+	assert.Empty(t, d.groups)
+
+	var nilDeduper *deduper
+	admitted, _ := nilDeduper.admit(Entry{})
+	assert.True(t, admitted)
+}
+
+func TestDeduper_IncludeStack(t *testing.T) {
+	d := newDeduper(&Dedup{IncludeStack: true})
+	now := time.Now()
+
+	admitted, _ := d.admit(Entry{Level: ErrorLevel, Message: "failed", Stack: "a.go:1", Time: now})
+	assert.True(t, admitted)
+	admitted, _ = d.admit(Entry{Level: ErrorLevel, Message: "failed", Stack: "b.go:2", Time: now})
+	assert.True(t, admitted, "different stacks are different fingerprints")
+	admitted, _ = d.admit(Entry{Level: ErrorLevel, Message: "failed", Stack: "a.go:1", Time: now})
+	assert.False(t, admitted)
+}
+
+func TestDedup_PerAdapterCollapseOnStop(t *testing.T) {
+	file := &fakeLogger{}
+	telegram := &fakeLogger{}
+
+	cl, err := New(AdapterSetting{Setting: testSetting{file}, Name: "file"},
+		AdapterSetting{Setting: testSetting{telegram}, Name: "telegram", Dedup: &Dedup{Window: time.Minute}})
+	require.NoError(t, err)
+
+	for i := 0; i < 5; i++ {
+		cl.Error("db down", nil)
+	}
+	results := cl.LogSync(ErrorLevel, "db down", nil)
+	cl.Stop()
+
+	assert.NoError(t, results["file"])
+	assert.ErrorIs(t, results["telegram"], ErrEntrySuppressed)
+	assert.Len(t, file.errorCalls, 6)
+	require.Len(t, telegram.errorCalls, 2)
+	assert.Nil(t, telegram.errorCalls[0].context[RepeatCountKey])
+	assert.Equal(t, 5, telegram.errorCalls[1].context[RepeatCountKey])
+}
@@ -0,0 +1,76 @@
+package composite_logger
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// delivery tracks an entry through the adapter queues so a caller can wait until every adapter has handled it.
+// All methods are safe to call on a nil delivery, which is what fire-and-forget entries carry.
+type delivery struct {
+	remaining atomic.Int64
+	once      sync.Once
+	done      chan struct{}
+
+	mu      sync.Mutex
+	results map[string]error
+}
+
+// newDelivery creates a delivery that completes after the given number of adapters have handled the entry.
+func newDelivery(adapters int) *delivery {
+	d := &delivery{done: make(chan struct{})}
+	d.remaining.Store(int64(adapters))
+	if adapters == 0 {
+		d.finish()
+	}
+
+	return d
+}
+
+// ack marks the entry as handled by one adapter, whether it was delivered or dropped.
+func (d *delivery) ack() {
+	if d == nil {
+		return
+	}
+
+	if d.remaining.Add(-1) <= 0 {
+		d.finish()
+	}
+}
+
+// complete records the result of the named adapter and marks the entry as handled by it.
+func (d *delivery) complete(adapter string, err error) {
+	if d == nil {
+		return
+	}
+
+	d.mu.Lock()
+	if d.results == nil {
+		d.results = make(map[string]error)
+	}
+	d.results[adapter] = err
+	d.mu.Unlock()
+
+	d.ack()
+}
+
+// result reports whether the named adapter has completed and the error it completed with.
+func (d *delivery) result(adapter string) (bool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	err, ok := d.results[adapter]
+	return ok, err
+}
+
+// finish completes the delivery regardless of the adapters still pending,
+// e.g. when the entry was dropped before reaching the adapter queues.
+func (d *delivery) finish() {
+	if d == nil {
+		return
+	}
+
+	d.once.Do(func() {
+		close(d.done)
+	})
+}
@@ -0,0 +1,57 @@
+package composite_logger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func isDone(d *delivery) bool {
+	select {
+	case <-d.done:
+		return true
+	default:
+		return false
+	}
+}
+
+func TestDelivery_CompletesAfterAllAcks(t *testing.T) {
+	d := newDelivery(2)
+
+	d.ack()
+	assert.False(t, isDone(d))
+	d.ack()
+	assert.True(t, isDone(d))
+}
+
+func TestDelivery_FinishIsIdempotent(t *testing.T) {
+	d := newDelivery(3)
+
+	d.finish()
+	d.ack()
+	d.finish()
+	assert.True(t, isDone(d))
+}
+
+func TestDelivery_WithoutAdaptersIsDone(t *testing.T) {
+	assert.True(t, isDone(newDelivery(0)))
+}
+
+func TestDelivery_NilIsNoop(t *testing.T) {
+	var d *delivery
+
+	assert.NotPanics(t, func() {
+		d.ack()
+		d.finish()
+	})
+}
+
+func TestQueue_ReleasesDeliveryOfDroppedEntries(t *testing.T) {
+	q := newQueue(1, OverflowDropNewest, 0, (*delivery).finish)
+	d := newDelivery(2)
+
+	q.push(Entry{Message: "fills the queue"})
+	q.push(Entry{Message: "dropped", delivery: d})
+
+	assert.True(t, isDone(d))
+}
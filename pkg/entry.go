@@ -0,0 +1,163 @@
+package composite_logger
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/Consolushka/golang.composite_logger/pkg/ports"
+)
+
+// Entry is a single log record as handed over to adapters.
+type Entry struct {
+	// Level is the severity of the entry.
+	Level Level
+	// Time is the moment the log call was made, not the moment of delivery.
+	Time time.Time
+	// Message is the log message.
+	Message string
+	// Fields holds the context passed with the log call. Adapters must treat it as read-only.
+	Fields map[string]interface{}
+	// Logger is the hierarchical name of the logger created by Named, empty for root loggers.
+	// For compatibility it is also present in Fields under the "logger" key.
+	Logger string
+	// Caller is the location of the log call in the application code.
+	Caller Caller
+	// Stack is the stack trace captured for Error and Fatal entries.
+	// For compatibility it is also present in Fields under the "stackTrace" key.
+	Stack string
+	// Sequence increases by one with every entry accepted by the same CompositeLogger.
+	Sequence uint64
+	// Attrs holds the typed fields passed to InfoFields and the other *Fields methods, in call order.
+	// Fields then holds only the bound fields, without the "logger" and "stackTrace" keys.
+	// Adapters not implementing AttrLogger never see Attrs; they receive everything merged into Fields.
+	Attrs []Field
+
+	// delivery is set for entries whose caller waits until all adapters have handled them.
+	delivery *delivery
+	// barrier marks a flush request travelling through the queues instead of a log record.
+	barrier context.Context
+	// internal marks entries produced by the logger itself, such as summaries; they are never sampled.
+	internal bool
+	// spoolID identifies the entry in the spool of the adapter it was queued for, zero if it is not spooled.
+	spoolID uint64
+	// typed marks entries logged with typed fields whose Fields do not include Attrs, the logger name and the stack yet.
+	typed bool
+}
+
+// Caller describes the source location of a log call.
+type Caller struct {
+	// Function is the fully qualified name of the calling function.
+	Function string
+	// File is the absolute path of the calling source file.
+	File string
+	// Line is the line number within File.
+	Line int
+}
+
+// String returns the caller in "file:line" form, or an empty string if the caller is unknown.
+func (c Caller) String() string {
+	if c.File == "" {
+		return ""
+	}
+
+	return c.File + ":" + strconv.Itoa(c.Line)
+}
+
+// EntryLogger is the structured counterpart of ports.Logger.
+// Adapters implementing it receive every entry through Log instead of the per-level methods,
+// so they can use the call timestamp, caller, level and sequence number as data.
+type EntryLogger interface {
+	// Log handles a single entry of any level.
+	Log(entry Entry)
+}
+
+// CheckedEntryLogger is an optional extension of EntryLogger for adapters that can tell whether an entry was written.
+// The returned error is reported to Options.ErrorHandler and returned by LogSync.
+type CheckedEntryLogger interface {
+	EntryLogger
+	// TryLog handles a single entry of any level and returns the write error, if any.
+	TryLog(entry Entry) error
+}
+
+// AttrLogger is an optional extension of EntryLogger for adapters that read typed fields from Entry.Attrs,
+// so entries logged with InfoFields and the other *Fields methods reach them without being converted to a map.
+// Entries logged with a context map, and entries whose fields were changed by redaction or processors,
+// are still passed to Log or TryLog.
+type AttrLogger interface {
+	EntryLogger
+	// LogAttrs handles a single entry with typed fields and returns the write error, if any.
+	// Entry.FieldsMap returns the fields as the map other adapters receive.
+	LogAttrs(entry Entry) error
+}
+
+// legacyLogger adapts a four-method ports.Logger to CheckedEntryLogger.
+type legacyLogger struct {
+	logger ports.Logger
+}
+
+// asEntryLogger returns the adapter itself if it implements EntryLogger and a legacy shim otherwise.
+func asEntryLogger(logger ports.Logger) EntryLogger {
+	if entryLogger, ok := logger.(EntryLogger); ok {
+		return entryLogger
+	}
+
+	return legacyLogger{logger: logger}
+}
+
+// TryLog calls the checked adapter method matching the entry level if the adapter implements ports.CheckedLogger,
+// or ports.CheckedVerboseLogger for debug and trace entries, and Log otherwise.
+func (l legacyLogger) TryLog(entry Entry) error {
+	checked, ok := l.logger.(ports.CheckedLogger)
+	if !ok {
+		l.Log(entry)
+		return nil
+	}
+
+	switch entry.Level {
+	case TraceLevel, DebugLevel:
+		verbose, ok := l.logger.(ports.CheckedVerboseLogger)
+		if !ok {
+			l.Log(entry)
+			return nil
+		}
+		if entry.Level == TraceLevel {
+			return verbose.TryTrace(entry.Message, entry.Fields)
+		}
+		return verbose.TryDebug(entry.Message, entry.Fields)
+	case InfoLevel:
+		return checked.TryInfo(entry.Message, entry.Fields)
+	case WarningLevel:
+		return checked.TryWarn(entry.Message, entry.Fields)
+	case ErrorLevel:
+		return checked.TryError(entry.Message, entry.Fields)
+	case FatalLevel:
+		return checked.TryFatal(entry.Message, entry.Fields)
+	default:
+		l.Log(entry)
+		return nil
+	}
+}
+
+// Log calls the adapter method matching the entry level.
+// Debug and trace entries only reach adapters implementing ports.VerboseLogger.
+func (l legacyLogger) Log(entry Entry) {
+	switch entry.Level {
+	case TraceLevel:
+		if verbose, ok := l.logger.(ports.VerboseLogger); ok {
+			verbose.Trace(entry.Message, entry.Fields)
+		}
+	case DebugLevel:
+		if verbose, ok := l.logger.(ports.VerboseLogger); ok {
+			verbose.Debug(entry.Message, entry.Fields)
+		}
+	case InfoLevel:
+		l.logger.Info(entry.Message, entry.Fields)
+	case WarningLevel:
+		l.logger.Warn(entry.Message, entry.Fields)
+	case ErrorLevel:
+		l.logger.Error(entry.Message, entry.Fields)
+	case FatalLevel:
+		l.logger.Fatal(entry.Message, entry.Fields)
+	}
+}
@@ -0,0 +1,83 @@
+package composite_logger
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type entryRecorder struct {
+	mu      sync.Mutex
+	entries []Entry
+}
+
+func (r *entryRecorder) Log(entry Entry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, entry)
+}
+
+// entryOnlyLogger implements EntryLogger next to the mandatory ports.Logger methods,
+// which must not be called by the core.
+type entryOnlyLogger struct {
+	fakeLogger
+	entryRecorder
+}
+
+func TestEntryLogger_ReceivesStructuredEntries(t *testing.T) {
+	l := &entryOnlyLogger{}
+	cl, err := New(testSetting{l})
+	require.NoError(t, err)
+
+	before := time.Now()
+	cl.Info("first", map[string]interface{}{"k": "v"})
+	cl.Error("second", nil)
+	cl.Stop()
+
+	assert.Empty(t, l.infoCalls)
+	assert.Empty(t, l.errorCalls)
+	require.Len(t, l.entries, 2)
+
+	first, second := l.entries[0], l.entries[1]
+	assert.Equal(t, InfoLevel, first.Level)
+	assert.Equal(t, "v", first.Fields["k"])
+	assert.False(t, first.Time.Before(before))
+	assert.Equal(t, uint64(1), first.Sequence)
+	assert.Empty(t, first.Stack)
+
+	assert.Equal(t, ErrorLevel, second.Level)
+	assert.Equal(t, uint64(2), second.Sequence)
+	assert.NotEmpty(t, second.Stack)
+	assert.Equal(t, second.Stack, second.Fields["stackTrace"])
+}
+
+func TestLegacyLogger_DispatchesByLevel(t *testing.T) {
+	legacy := &fakeLogger{}
+	shim := asEntryLogger(legacy)
+
+	shim.Log(Entry{Level: InfoLevel, Message: "info"})
+	shim.Log(Entry{Level: WarningLevel, Message: "warn"})
+	shim.Log(Entry{Level: ErrorLevel, Message: "error"})
+	shim.Log(Entry{Level: FatalLevel, Message: "fatal"})
+	shim.Log(Entry{Level: DebugLevel, Message: "debug"})
+
+	assert.Len(t, legacy.infoCalls, 1)
+	assert.Len(t, legacy.warnCalls, 1)
+	assert.Len(t, legacy.errorCalls, 1)
+	assert.Len(t, legacy.fatalCalls, 1)
+}
+
+func TestAsEntryLogger_PrefersEntryLogger(t *testing.T) {
+	l := &entryOnlyLogger{}
+
+	assert.Same(t, l, asEntryLogger(l))
+	assert.IsType(t, legacyLogger{}, asEntryLogger(&fakeLogger{}))
+}
+
+func TestCaller_String(t *testing.T) {
+	assert.Empty(t, Caller{}.String())
+	assert.Equal(t, "/app/main.go:42", Caller{File: "/app/main.go", Line: 42}.String())
+}
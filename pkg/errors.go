@@ -0,0 +1,99 @@
+package composite_logger
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/Consolushka/golang.composite_logger/pkg/ports"
+)
+
+var (
+	// ErrAdapterPanic is wrapped by errors reported when an adapter panics while handling an entry.
+	ErrAdapterPanic = errors.New("adapter panicked")
+	// ErrAdapterTimeout is reported when an adapter does not handle an entry within AdapterSetting.Timeout.
+	ErrAdapterTimeout = errors.New("adapter timed out")
+	// ErrAdapterQuarantined is reported once when an adapter is disabled after repeated panics.
+	ErrAdapterQuarantined = errors.New("adapter quarantined")
+	// ErrAdapterFailover is wrapped by errors reported when an adapter of a FailoverSetting fails
+	// and the entry is delivered by a later one.
+	ErrAdapterFailover = errors.New("adapter failed over")
+	// ErrRetriesExhausted is wrapped by errors returned by a RetrySetting adapter when every attempt failed.
+	ErrRetriesExhausted = errors.New("retries exhausted")
+	// ErrCircuitOpen is returned by a RetrySetting adapter for entries rejected while its circuit breaker is open.
+	ErrCircuitOpen = errors.New("circuit breaker open")
+	// ErrSpool is wrapped by errors reported when an entry cannot be written to or acknowledged in an AdapterSetting.Spool.
+	// The entry is still delivered from memory.
+	ErrSpool = errors.New("spool failed")
+	// ErrSpoolInUse is returned by New when the Dir of an AdapterSetting.Spool is already open in another logger
+	// of the process. Stop that logger first; Init does so for the logger it replaces.
+	ErrSpoolInUse = errors.New("spool directory in use")
+	// ErrEntryDropped is returned by LogSync for adapters whose queue discarded the entry.
+	ErrEntryDropped = errors.New("log entry dropped")
+	// ErrEntryFiltered is returned by LogSync for adapters that did not receive the entry because a Processor dropped it.
+	ErrEntryFiltered = errors.New("log entry filtered")
+	// ErrEntrySuppressed is returned by LogSync for adapters that did not receive the entry because of Sampling
+	// or a RateLimit, or that hold it back as a repeat because of Dedup.
+	ErrEntrySuppressed = errors.New("log entry suppressed")
+	// ErrEntryNotRouted is returned by LogSync for adapters that did not receive the entry because of Options.Routes.
+	ErrEntryNotRouted = errors.New("log entry not routed to adapter")
+	// ErrProcessorPanic is wrapped by errors reported when a Processor panics.
+	// The entry is passed on as it was before the panicking processor.
+	ErrProcessorPanic = errors.New("processor panicked")
+	// ErrRoutePanic is wrapped by errors reported when the Match function of a Route panics.
+	ErrRoutePanic = errors.New("route condition panicked")
+	// ErrLoggerStopped is returned by LogSync for every adapter once the logger has been stopped.
+	ErrLoggerStopped = errors.New("logger stopped")
+)
+
+// AdapterError describes a failure of a single adapter to handle an entry.
+type AdapterError struct {
+	// Adapter is the name of the failing adapter, empty for failures of the processors in Options.Processors
+	// and of Options.Routes.
+	Adapter string
+	// Entry is the entry that could not be handled.
+	Entry Entry
+	// Err is the underlying error.
+	Err error
+	// Stack is the stack trace of the adapter goroutine if the adapter panicked.
+	Stack string
+}
+
+// Error returns a description including the adapter name and the underlying error.
+func (e *AdapterError) Error() string {
+	if e.Adapter == "" {
+		return fmt.Sprintf("composite_logger: %v", e.Err)
+	}
+
+	return fmt.Sprintf("composite_logger: adapter %q: %v", e.Adapter, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *AdapterError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorHandler receives adapter failures. It is called from the adapter's worker goroutine,
+// so it should return quickly and must not log through the same logger synchronously.
+type ErrorHandler func(err *AdapterError)
+
+// defaultErrorHandler writes adapter failures to standard error.
+func defaultErrorHandler(err *AdapterError) {
+	_, _ = fmt.Fprintln(os.Stderr, err)
+}
+
+// reportError passes the error to the handler, shielding the calling worker from panics in the handler itself.
+func reportError(handler ErrorHandler, err *AdapterError) {
+	defer func() {
+		_ = recover()
+	}()
+
+	handler(err)
+}
+
+// ErrorReporter is implemented by adapters that detect failures themselves, such as a rejected HTTP request.
+// The core passes a handler that forwards those failures to Options.ErrorHandler.
+type ErrorReporter interface {
+	// WithErrorHandler returns a copy of the adapter that reports its failures to handler.
+	WithErrorHandler(handler func(entry Entry, err error)) ports.Logger
+}
@@ -0,0 +1,113 @@
+package composite_logger
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/Consolushka/golang.composite_logger/pkg/ports"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// panickingLogger panics on every call.
+type panickingLogger struct{}
+
+func (panickingLogger) Info(string, map[string]interface{})  { panic("boom") }
+func (panickingLogger) Warn(string, map[string]interface{})  { panic("boom") }
+func (panickingLogger) Error(string, map[string]interface{}) { panic("boom") }
+func (panickingLogger) Fatal(string, map[string]interface{}) { panic("boom") }
+
+// reportingLogger reports every entry as failed through the injected handler.
+type reportingLogger struct {
+	fakeLogger
+	handler func(entry Entry, err error)
+}
+
+func (r *reportingLogger) Log(entry Entry) {
+	r.handler(entry, errors.New("rejected"))
+}
+
+func (r *reportingLogger) WithErrorHandler(handler func(entry Entry, err error)) ports.Logger {
+	return &reportingLogger{handler: handler}
+}
+
+// errorRecorder collects reported adapter errors.
+type errorRecorder struct {
+	mu     sync.Mutex
+	errors []*AdapterError
+}
+
+func (r *errorRecorder) handle(err *AdapterError) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.errors = append(r.errors, err)
+}
+
+func TestAdapterPanic_IsIsolatedAndReported(t *testing.T) {
+	healthy := &fakeLogger{}
+	recorder := &errorRecorder{}
+
+	cl, err := NewWithOptions(Options{ErrorHandler: recorder.handle},
+		AdapterSetting{Setting: testSetting{panickingLogger{}}, Name: "broken"}, testSetting{healthy})
+	require.NoError(t, err)
+
+	cl.Info("first", nil)
+	cl.Info("second", nil)
+	cl.Stop()
+
+	require.Len(t, healthy.infoCalls, 2)
+	require.Len(t, recorder.errors, 2)
+	assert.Equal(t, "broken", recorder.errors[0].Adapter)
+	assert.Equal(t, "first", recorder.errors[0].Entry.Message)
+	assert.ErrorIs(t, recorder.errors[0], ErrAdapterPanic)
+	assert.NotEmpty(t, recorder.errors[0].Stack)
+}
+
+func TestAdapterPanic_QuarantinesAfterConsecutivePanics(t *testing.T) {
+	recorder := &errorRecorder{}
+
+	cl, err := NewWithOptions(Options{ErrorHandler: recorder.handle, QuarantineAfter: 2}, testSetting{panickingLogger{}})
+	require.NoError(t, err)
+
+	for i := 0; i < 5; i++ {
+		cl.Info("message", nil)
+	}
+	cl.Stop()
+
+	require.Len(t, recorder.errors, 3)
+	assert.ErrorIs(t, recorder.errors[2], ErrAdapterQuarantined)
+	require.Len(t, cl.Health(), 1)
+	assert.True(t, cl.Health()[0].Quarantined)
+	assert.Equal(t, uint64(2), cl.Health()[0].Panics)
+}
+
+func TestAdapterError_ReportedByAdapter(t *testing.T) {
+	recorder := &errorRecorder{}
+
+	cl, err := NewWithOptions(Options{ErrorHandler: recorder.handle}, testSetting{&reportingLogger{}})
+	require.NoError(t, err)
+
+	cl.Warn("rejected message", nil)
+	cl.Stop()
+
+	require.Len(t, recorder.errors, 1)
+	assert.Equal(t, "test", recorder.errors[0].Adapter)
+	assert.EqualError(t, recorder.errors[0], `composite_logger: adapter "test": rejected`)
+	assert.Equal(t, uint64(1), cl.Health()[0].Failures)
+}
+
+func TestAdapterError_PanickingHandlerDoesNotKillWorker(t *testing.T) {
+	l := &fakeLogger{}
+
+	cl, err := NewWithOptions(Options{ErrorHandler: func(*AdapterError) { panic("handler") }},
+		testSetting{panickingLogger{}}, testSetting{l})
+	require.NoError(t, err)
+
+	cl.Info("first", nil)
+	cl.Info("second", nil)
+	cl.Stop()
+
+	assert.Len(t, l.infoCalls, 2)
+}
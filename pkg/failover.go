@@ -0,0 +1,254 @@
+package composite_logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/Consolushka/golang.composite_logger/pkg/ports"
+)
+
+// defaultFailoverRetryAfter is how long a failed adapter of a FailoverSetting is skipped when no interval is configured.
+const defaultFailoverRetryAfter = 30 * time.Second
+
+// FailoverSetting combines an ordered list of adapters into one that delivers every entry to the first adapter
+// able to handle it, e.g. Telegram with a local file as a fallback.
+// An adapter that returns an error, panics or exceeds Timeout is skipped for RetryAfter; the first entry after that
+// tries it again, so delivery switches back to the primary as soon as it is healthy. The last adapter is always tried.
+// An adapter keeps at most one call in flight: while a call that exceeded Timeout is still running, the adapter
+// counts as failed without being called again.
+//
+// Only failures an adapter can report trigger a failover: errors of adapters implementing CheckedEntryLogger
+// or ports.CheckedLogger, such as the Telegram adapter without a send queue, panics and timeouts.
+// Failures followed by a successful delivery are reported to Options.ErrorHandler; LogSync reports an error
+// only if every adapter failed.
+//
+// Usage:
+//
+//	composite_logger.Init(composite_logger.FailoverSetting{
+//		Settings: []composite_logger.LoggerSetting{
+//			setting.TelegramSetting{Enabled: true, BotKey: "KEY", ChatId: 1},
+//			setting.FileSetting{Enabled: true, Path: "logs/telegram-spool.log"},
+//		},
+//		Timeout: 5 * time.Second,
+//	})
+type FailoverSetting struct {
+	// Settings are the adapters in order of preference. Disabled ones are left out.
+	Settings []LoggerSetting
+	// Timeout bounds a single delivery attempt before the next adapter is tried. Zero means no limit.
+	Timeout time.Duration
+	// RetryAfter is how long a failed adapter is skipped before it is tried again (default: 30 seconds).
+	RetryAfter time.Duration
+}
+
+// IsEnabled reports whether at least one of the adapters is enabled.
+func (f FailoverSetting) IsEnabled() bool {
+	for _, s := range f.Settings {
+		if s != nil && s.IsEnabled() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// InitLogger initializes the enabled adapters and returns the logger that fails over between them.
+func (f FailoverSetting) InitLogger() Logger {
+	l := &failoverLogger{timeout: f.Timeout, retryAfter: f.RetryAfter}
+	if l.retryAfter <= 0 {
+		l.retryAfter = defaultFailoverRetryAfter
+	}
+
+	for _, s := range f.Settings {
+		if s == nil || !s.IsEnabled() {
+			continue
+		}
+
+		adapter := s.InitLogger()
+		l.members = append(l.members, &failoverMember{
+			name:    adapterSetting(s).name(),
+			adapter: adapter,
+			logger:  asEntryLogger(adapter),
+		})
+	}
+
+	return l
+}
+
+// failoverMember is one adapter of a failover chain.
+type failoverMember struct {
+	name    string
+	adapter ports.Logger
+	logger  EntryLogger
+	// retryAt is the time in Unix nanoseconds before which the adapter is skipped after a failure.
+	retryAt atomic.Int64
+	// busy is set while a call to the adapter is running, including a call try stopped waiting for.
+	busy atomic.Bool
+}
+
+// failoverLogger implements FailoverSetting.
+type failoverLogger struct {
+	members    []*failoverMember
+	timeout    time.Duration
+	retryAfter time.Duration
+	onError    func(entry Entry, err error)
+}
+
+func (f *failoverLogger) Info(message string, context map[string]interface{}) {
+	f.Log(Entry{Level: InfoLevel, Time: time.Now(), Message: message, Fields: context})
+}
+
+func (f *failoverLogger) Warn(message string, context map[string]interface{}) {
+	f.Log(Entry{Level: WarningLevel, Time: time.Now(), Message: message, Fields: context})
+}
+
+func (f *failoverLogger) Error(message string, context map[string]interface{}) {
+	f.Log(Entry{Level: ErrorLevel, Time: time.Now(), Message: message, Fields: context})
+}
+
+func (f *failoverLogger) Fatal(message string, context map[string]interface{}) {
+	f.Log(Entry{Level: FatalLevel, Time: time.Now(), Message: message, Fields: context})
+}
+
+// Log delivers the entry to the first adapter able to handle it.
+func (f *failoverLogger) Log(entry Entry) {
+	_ = f.TryLog(entry)
+}
+
+// TryLog delivers the entry to the first adapter able to handle it and returns the errors of all adapters
+// if none could.
+func (f *failoverLogger) TryLog(entry Entry) error {
+	now := time.Now()
+	var failures []error
+	for i, m := range f.members {
+		if i < len(f.members)-1 && now.UnixNano() < m.retryAt.Load() {
+			continue
+		}
+
+		err := m.try(entry, f.timeout)
+		if err == nil {
+			m.retryAt.Store(0)
+			f.reportFailures(entry, failures)
+			return nil
+		}
+
+		m.retryAt.Store(now.Add(f.retryAfter).UnixNano())
+		failures = append(failures, fmt.Errorf("%s: %w", m.name, err))
+	}
+
+	return errors.Join(failures...)
+}
+
+// reportFailures reports the failures of adapters whose entry was delivered by a later one.
+func (f *failoverLogger) reportFailures(entry Entry, failures []error) {
+	if f.onError == nil {
+		return
+	}
+
+	for _, err := range failures {
+		f.onError(entry, fmt.Errorf("%w: %w", ErrAdapterFailover, err))
+	}
+}
+
+// WithErrorHandler returns a copy of the logger that reports failures followed by a successful delivery to handler.
+// Adapters of the chain that report failures themselves report them to handler as well.
+func (f *failoverLogger) WithErrorHandler(handler func(entry Entry, err error)) ports.Logger {
+	clone := &failoverLogger{timeout: f.timeout, retryAfter: f.retryAfter, onError: handler}
+	for _, m := range f.members {
+		member := &failoverMember{name: m.name, adapter: m.adapter, logger: m.logger}
+		if reporter, ok := m.adapter.(ErrorReporter); ok {
+			name := m.name
+			member.adapter = reporter.WithErrorHandler(func(entry Entry, err error) {
+				handler(entry, fmt.Errorf("%s: %w", name, err))
+			})
+			member.logger = asEntryLogger(member.adapter)
+		}
+		clone.members = append(clone.members, member)
+	}
+
+	return clone
+}
+
+// abortOn passes the Shutdown signal on to the adapters of the chain that wait between attempts.
+func (f *failoverLogger) abortOn(aborted <-chan struct{}) {
+	for _, m := range f.members {
+		if a, ok := m.adapter.(abortable); ok {
+			a.abortOn(aborted)
+		}
+	}
+}
+
+// Flush flushes every adapter of the chain that buffers messages.
+func (f *failoverLogger) Flush(ctx context.Context) error {
+	var errs []error
+	for _, m := range f.members {
+		if flusher, ok := m.adapter.(ports.Flusher); ok {
+			if err := flusher.Flush(ctx); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", m.name, err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Close closes every adapter of the chain that holds resources.
+func (f *failoverLogger) Close(ctx context.Context) error {
+	var errs []error
+	for _, m := range f.members {
+		if closer, ok := m.adapter.(ports.Closer); ok {
+			if err := closer.Close(ctx); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", m.name, err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// try delivers the entry to the adapter, giving up waiting once the timeout expires.
+// It fails without calling the adapter while an earlier call is still running.
+func (m *failoverMember) try(entry Entry, timeout time.Duration) error {
+	if !m.busy.CompareAndSwap(false, true) {
+		return fmt.Errorf("%w: previous call still running", ErrAdapterTimeout)
+	}
+	if timeout <= 0 {
+		defer m.busy.Store(false)
+		return m.call(entry)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		err := m.call(entry)
+		m.busy.Store(false)
+		done <- err
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case err := <-done:
+		return err
+	case <-timer.C:
+		return ErrAdapterTimeout
+	}
+}
+
+// call invokes the adapter and turns a panic into an error.
+func (m *failoverMember) call(entry Entry) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", ErrAdapterPanic, r)
+		}
+	}()
+
+	if checked, ok := m.logger.(CheckedEntryLogger); ok {
+		return checked.TryLog(entry)
+	}
+
+	m.logger.Log(entry)
+	return nil
+}
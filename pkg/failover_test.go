@@ -0,0 +1,120 @@
+package composite_logger
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFailover_FallsThroughAndSwitchesBack(t *testing.T) {
+	primary := &checkedLogger{err: errors.New("telegram unreachable")}
+	spool := &fakeLogger{}
+	recorder := &errorRecorder{}
+
+	cl, err := NewWithOptions(Options{ErrorHandler: recorder.handle}, FailoverSetting{
+		Settings:   []LoggerSetting{AdapterSetting{Setting: testSetting{primary}, Name: "telegram"}, testSetting{spool}},
+		RetryAfter: 20 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	defer cl.Stop()
+
+	assert.NoError(t, cl.LogSync(InfoLevel, "first", nil)["failover"])
+	assert.NoError(t, cl.LogSync(InfoLevel, "second", nil)["failover"], "the failed primary is skipped")
+	require.Len(t, spool.infoCalls, 2)
+
+	require.Len(t, recorder.errors, 1)
+	assert.Equal(t, "failover", recorder.errors[0].Adapter)
+	assert.ErrorIs(t, recorder.errors[0].Err, ErrAdapterFailover)
+	assert.ErrorContains(t, recorder.errors[0].Err, "telegram: telegram unreachable")
+
+	primary.err = nil
+	time.Sleep(30 * time.Millisecond)
+	assert.NoError(t, cl.LogSync(InfoLevel, "third", nil)["failover"])
+
+	assert.Len(t, spool.infoCalls, 2, "delivery switched back to the healthy primary")
+}
+
+func TestFailover_ReportsErrorWhenEveryAdapterFails(t *testing.T) {
+	cl, err := NewWithOptions(Options{ErrorHandler: func(*AdapterError) {}}, FailoverSetting{
+		Settings: []LoggerSetting{
+			AdapterSetting{Setting: testSetting{&checkedLogger{err: errors.New("unreachable")}}, Name: "telegram"},
+			AdapterSetting{Setting: testSetting{panickingLogger{}}, Name: "spool"},
+		},
+	})
+	require.NoError(t, err)
+	defer cl.Stop()
+
+	result := cl.LogSync(ErrorLevel, "lost", nil)["failover"]
+
+	assert.ErrorContains(t, result, "telegram: unreachable")
+	assert.ErrorIs(t, result, ErrAdapterPanic)
+}
+
+func TestFailover_TimeoutMovesToNextAdapter(t *testing.T) {
+	hung := blockingLogger{release: make(chan struct{})}
+	defer close(hung.release)
+	spool := &fakeLogger{}
+
+	cl, err := NewWithOptions(Options{ErrorHandler: func(*AdapterError) {}}, FailoverSetting{
+		Settings: []LoggerSetting{testSetting{hung}, testSetting{spool}},
+		Timeout:  10 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	defer cl.Stop()
+
+	assert.NoError(t, cl.LogSync(WarningLevel, "slow network", nil)["failover"])
+	assert.Len(t, spool.warnCalls, 1)
+}
+
+func TestFailover_HungAdapterKeepsOneCallInFlight(t *testing.T) {
+	hung := &countingHungLogger{release: make(chan struct{})}
+	spool := &fakeLogger{}
+
+	cl, err := NewWithOptions(Options{ErrorHandler: func(*AdapterError) {}}, FailoverSetting{
+		Settings:   []LoggerSetting{testSetting{hung}, testSetting{spool}},
+		Timeout:    10 * time.Millisecond,
+		RetryAfter: time.Nanosecond,
+	})
+	require.NoError(t, err)
+	defer cl.Stop()
+
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, cl.LogSync(WarningLevel, "slow network", nil)["failover"])
+	}
+
+	assert.Len(t, spool.warnCalls, 3)
+	assert.Equal(t, int64(1), hung.calls.Load(), "the hung adapter is not called again while its call is running")
+
+	close(hung.release)
+	assert.Eventually(t, func() bool {
+		cl.LogSync(WarningLevel, "recovered", nil)
+		return hung.calls.Load() == 2
+	}, time.Second, 5*time.Millisecond, "the adapter is tried again once the hung call returned")
+}
+
+func TestFailover_FlushesAndClosesEveryAdapter(t *testing.T) {
+	primary := &lifecycleLogger{}
+	spool := &lifecycleLogger{}
+
+	cl, err := New(FailoverSetting{Settings: []LoggerSetting{testSetting{primary}, testSetting{spool}}})
+	require.NoError(t, err)
+
+	require.NoError(t, cl.Flush(context.Background()))
+	_, err = cl.Shutdown(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, primary.flushed, "Shutdown flushes before closing")
+	assert.Equal(t, 2, spool.flushed)
+	assert.Equal(t, 1, primary.closed)
+	assert.Equal(t, 1, spool.closed)
+}
+
+func TestFailoverSetting_IsEnabled(t *testing.T) {
+	assert.False(t, FailoverSetting{}.IsEnabled())
+	assert.False(t, FailoverSetting{Settings: []LoggerSetting{AdapterSetting{}}}.IsEnabled())
+	assert.True(t, FailoverSetting{Settings: []LoggerSetting{AdapterSetting{}, testSetting{&fakeLogger{}}}}.IsEnabled())
+}
@@ -0,0 +1,184 @@
+package composite_logger
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// FieldType tells which member of Field holds the value.
+type FieldType uint8
+
+const (
+	// AnyType fields keep the value in Field.Interface as is.
+	AnyType FieldType = iota
+	// StringType fields keep the value in Field.String.
+	StringType
+	// IntType fields keep an int in Field.Integer.
+	IntType
+	// Int64Type fields keep an int64 in Field.Integer.
+	Int64Type
+	// Float64Type fields keep the bits of a float64 in Field.Integer.
+	Float64Type
+	// BoolType fields keep 1 for true and 0 for false in Field.Integer.
+	BoolType
+	// DurationType fields keep the nanoseconds of a time.Duration in Field.Integer.
+	DurationType
+	// TimeType fields keep a time.Time in Field.Interface.
+	TimeType
+	// ErrorType fields keep an error in Field.Interface.
+	ErrorType
+)
+
+// ErrorKey is the field under which F.Err logs an error; it is also where the stack trace of an error is looked up.
+const ErrorKey = "error"
+
+// Field is a typed key-value pair passed to InfoFields and the other *Fields methods.
+// Scalar values are stored without boxing them into an interface, so building fields does not allocate.
+type Field struct {
+	Key       string
+	Type      FieldType
+	Integer   int64
+	String    string
+	Interface interface{}
+}
+
+// Value returns the field value with its original type, e.g. an int for F.Int and a time.Duration for F.Duration.
+func (f Field) Value() interface{} {
+	switch f.Type {
+	case StringType:
+		return f.String
+	case IntType:
+		return int(f.Integer)
+	case Int64Type:
+		return f.Integer
+	case Float64Type:
+		return math.Float64frombits(uint64(f.Integer))
+	case BoolType:
+		return f.Integer == 1
+	case DurationType:
+		return time.Duration(f.Integer)
+	default:
+		return f.Interface
+	}
+}
+
+// fieldConstructors groups the Field constructors under F.
+type fieldConstructors struct{}
+
+// F holds the Field constructors.
+//
+// Usage:
+//
+//	composite_logger.InfoFields("user signed in", composite_logger.F.String("user", name), composite_logger.F.Duration("took", took))
+var F fieldConstructors
+
+// String returns a string field.
+func (fieldConstructors) String(key, value string) Field {
+	return Field{Key: key, Type: StringType, String: value}
+}
+
+// Stringer returns a field holding the result of value.String(), computed when the field is created.
+func (fieldConstructors) Stringer(key string, value fmt.Stringer) Field {
+	return Field{Key: key, Type: StringType, String: value.String()}
+}
+
+// Int returns an int field.
+func (fieldConstructors) Int(key string, value int) Field {
+	return Field{Key: key, Type: IntType, Integer: int64(value)}
+}
+
+// Int64 returns an int64 field.
+func (fieldConstructors) Int64(key string, value int64) Field {
+	return Field{Key: key, Type: Int64Type, Integer: value}
+}
+
+// Float64 returns a float64 field.
+func (fieldConstructors) Float64(key string, value float64) Field {
+	return Field{Key: key, Type: Float64Type, Integer: int64(math.Float64bits(value))}
+}
+
+// Bool returns a bool field.
+func (fieldConstructors) Bool(key string, value bool) Field {
+	f := Field{Key: key, Type: BoolType}
+	if value {
+		f.Integer = 1
+	}
+
+	return f
+}
+
+// Duration returns a time.Duration field.
+func (fieldConstructors) Duration(key string, value time.Duration) Field {
+	return Field{Key: key, Type: DurationType, Integer: int64(value)}
+}
+
+// Time returns a time.Time field.
+func (fieldConstructors) Time(key string, value time.Time) Field {
+	return Field{Key: key, Type: TimeType, Interface: value}
+}
+
+// Err returns a field holding err under the "error" key. A stack trace carried by err
+// is used for Error and Fatal entries, as with the "error" key of a context map.
+func (fieldConstructors) Err(err error) Field {
+	return Field{Key: ErrorKey, Type: ErrorType, Interface: err}
+}
+
+// NamedErr returns a field holding err under the given key.
+func (fieldConstructors) NamedErr(key string, err error) Field {
+	return Field{Key: key, Type: ErrorType, Interface: err}
+}
+
+// Any returns a field holding value as is.
+func (fieldConstructors) Any(key string, value interface{}) Field {
+	return Field{Key: key, Type: AnyType, Interface: value}
+}
+
+// FieldsMap returns the typed fields merged into the context map of the entry, together with the logger name
+// and the stack trace, i.e. the map adapters without AttrLogger receive. Typed fields win on conflicts.
+// For entries without typed fields it returns Fields.
+func (e Entry) FieldsMap() map[string]interface{} {
+	if !e.typed {
+		return e.Fields
+	}
+
+	fields := make(map[string]interface{}, len(e.Fields)+len(e.Attrs)+2)
+	for key, value := range e.Fields {
+		fields[key] = value
+	}
+	for _, attr := range e.Attrs {
+		fields[attr.Key] = attr.Value()
+	}
+	if e.Logger != "" {
+		fields[LoggerKey] = e.Logger
+	}
+	if e.Stack != "" {
+		fields["stackTrace"] = e.Stack
+	}
+
+	return fields
+}
+
+// flatten moves the typed fields of the entry into Fields, so code working on maps sees all of them.
+func (e Entry) flatten() Entry {
+	if !e.typed {
+		return e
+	}
+
+	e.Fields = e.FieldsMap()
+	e.Attrs = nil
+	e.typed = false
+
+	return e
+}
+
+// errorFromAttrs returns the error of the first field under the "error" key, if any.
+func errorFromAttrs(attrs []Field) interface{} {
+	for _, attr := range attrs {
+		if attr.Key == ErrorKey {
+			return attr.Value()
+		}
+	}
+
+	return nil
+}
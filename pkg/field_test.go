@@ -0,0 +1,114 @@
+package composite_logger
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// attrRecorder records the entries passed to LogAttrs and Log.
+type attrRecorder struct {
+	fakeLogger
+	mu      sync.Mutex
+	typed   []Entry
+	entries []Entry
+}
+
+func (a *attrRecorder) Log(entry Entry) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.entries = append(a.entries, entry)
+}
+
+func (a *attrRecorder) LogAttrs(entry Entry) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.typed = append(a.typed, entry)
+	return nil
+}
+
+func TestField_ValueKeepsType(t *testing.T) {
+	now := time.Now()
+	err := errors.New("boom")
+
+	assert.Equal(t, "alice", F.String("user", "alice").Value())
+	assert.Equal(t, 3, F.Int("n", 3).Value())
+	assert.Equal(t, int64(-7), F.Int64("n", -7).Value())
+	assert.Equal(t, 1.5, F.Float64("ratio", 1.5).Value())
+	assert.Equal(t, true, F.Bool("ok", true).Value())
+	assert.Equal(t, false, F.Bool("ok", false).Value())
+	assert.Equal(t, 2*time.Second, F.Duration("took", 2*time.Second).Value())
+	assert.Equal(t, now, F.Time("at", now).Value())
+	assert.Equal(t, "1s", F.Stringer("period", time.Second).Value())
+	assert.Equal(t, []int{1}, F.Any("ids", []int{1}).Value())
+
+	field := F.Err(err)
+	assert.Equal(t, ErrorKey, field.Key)
+	assert.Same(t, err, field.Value())
+}
+
+func TestInfoFields_MergedIntoMapForLegacyAdapters(t *testing.T) {
+	l := &fakeLogger{}
+	cl, err := New(testSetting{l})
+	require.NoError(t, err)
+
+	child := cl.With(map[string]interface{}{"component": "billing", "attempt": 1}).Named("invoices")
+	child.InfoFields("charged", F.String("user", "alice"), F.Int("attempt", 2))
+	child.ErrorFields("charge failed", F.Err(errors.New("declined")))
+	cl.Stop()
+
+	require.Len(t, l.infoCalls, 1)
+	assert.Equal(t, map[string]interface{}{
+		"component": "billing",
+		"attempt":   2,
+		"user":      "alice",
+		LoggerKey:   "invoices",
+	}, l.infoCalls[0].context)
+
+	require.Len(t, l.errorCalls, 1)
+	assert.EqualError(t, l.errorCalls[0].context[ErrorKey].(error), "declined")
+	assert.NotEmpty(t, l.errorCalls[0].context["stackTrace"])
+}
+
+func TestInfoFields_AttrLoggerReceivesTypedFields(t *testing.T) {
+	recorder := &attrRecorder{}
+	cl, err := New(testSetting{recorder})
+	require.NoError(t, err)
+
+	bound := map[string]interface{}{"component": "billing"}
+	cl.With(bound).InfoFields("charged", F.String("user", "alice"), F.Int("attempt", 2))
+	cl.Info("map entry", map[string]interface{}{"user": "bob"})
+	cl.Stop()
+
+	require.Len(t, recorder.typed, 1)
+	entry := recorder.typed[0]
+	assert.Equal(t, []Field{F.String("user", "alice"), F.Int("attempt", 2)}, entry.Attrs)
+	assert.Equal(t, bound, entry.Fields)
+	assert.Equal(t, map[string]interface{}{"component": "billing", "user": "alice", "attempt": 2}, entry.FieldsMap())
+
+	require.Len(t, recorder.entries, 1, "entries logged with a map are passed to Log")
+	assert.Nil(t, recorder.entries[0].Attrs)
+}
+
+func TestInfoFields_RedactedAndProcessedAsMap(t *testing.T) {
+	recorder := &attrRecorder{}
+	cl, err := NewWithOptions(Options{
+		Redaction:  []RedactionRule{RedactKeys(RedactMask, "password")},
+		Processors: []Processor{Service("billing")},
+	}, testSetting{recorder})
+	require.NoError(t, err)
+
+	cl.InfoFields("login", F.String("user", "alice"), F.String("password", "hunter2"))
+	cl.Stop()
+
+	require.Len(t, recorder.entries, 1)
+	assert.Equal(t, map[string]interface{}{
+		"user":     "alice",
+		"password": RedactedPlaceholder,
+		ServiceKey: "billing",
+	}, recorder.entries[0].Fields)
+}
@@ -0,0 +1,45 @@
+package composite_logger
+
+// AdapterStatus is a snapshot of the delivery state of a single adapter.
+type AdapterStatus struct {
+	// Name is the adapter name, see AdapterSetting.Name.
+	Name string
+	// Queued is the number of entries waiting in the adapter's queue.
+	Queued int
+	// Dropped is the number of entries discarded because the adapter's queue was full.
+	Dropped uint64
+	// Failures is the number of entries the adapter failed to handle.
+	Failures uint64
+	// Panics is the number of entries during which the adapter panicked.
+	Panics uint64
+	// Quarantined is true once the adapter has been disabled after repeated panics.
+	Quarantined bool
+	// Circuit is the state of the circuit breaker of a RetrySetting adapter, CircuitNone for other adapters.
+	Circuit CircuitState
+}
+
+// Health returns the delivery state of every adapter in registration order.
+//
+// Usage:
+//
+//	for _, status := range logger.Health() {
+//		fmt.Println(status.Name, status.Failures, status.Quarantined)
+//	}
+func (cl *CompositeLogger) Health() []AdapterStatus {
+	c, _ := cl.resolve()
+	if c == nil {
+		return nil
+	}
+
+	statuses := make([]AdapterStatus, 0, len(c.sinks))
+	for _, s := range c.sinks {
+		statuses = append(statuses, s.status())
+	}
+
+	return statuses
+}
+
+// Health returns the delivery state of every adapter of the global logger.
+func Health() []AdapterStatus {
+	return Default().Health()
+}
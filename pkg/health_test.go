@@ -0,0 +1,35 @@
+package composite_logger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealth_NamesAdapters(t *testing.T) {
+	cl, err := New(testSetting{&fakeLogger{}}, testSetting{&fakeLogger{}},
+		AdapterSetting{Setting: testSetting{&fakeLogger{}}, Name: "audit"})
+	require.NoError(t, err)
+	defer cl.Stop()
+
+	names := make([]string, 0, 3)
+	for _, status := range cl.Health() {
+		names = append(names, status.Name)
+	}
+
+	assert.Equal(t, []string{"test", "test#2", "audit"}, names)
+}
+
+func TestHealth_RejectsDuplicateNames(t *testing.T) {
+	_, err := New(AdapterSetting{Setting: testSetting{&fakeLogger{}}, Name: "audit"},
+		AdapterSetting{Setting: testSetting{&fakeLogger{}}, Name: "audit"})
+
+	assert.EqualError(t, err, `composite_logger: duplicate adapter name "audit"`)
+}
+
+func TestHealth_NilLogger(t *testing.T) {
+	var cl *CompositeLogger
+
+	assert.Nil(t, cl.Health())
+}
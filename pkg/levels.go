@@ -2,6 +2,7 @@ package composite_logger
 
 import (
 	"errors"
+	"log/slog"
 	"strings"
 
 	"github.com/sirupsen/logrus"
@@ -11,6 +12,11 @@ import (
 type Level int
 
 const (
+	// TraceLevel is used for the most fine-grained diagnostics, such as values inside hot loops.
+	// Trace and Debug sit below the zero value so that settings without an explicit LowerLevel keep logging from Info up.
+	TraceLevel Level = -2
+	// DebugLevel is used for verbose diagnostics that are useful while developing or troubleshooting.
+	DebugLevel Level = -1
 	// InfoLevel is used for informational messages that highlight the progress of the application.
 	InfoLevel Level = 1
 	// WarningLevel is used for potentially harmful situations or important notices.
@@ -24,6 +30,8 @@ const (
 // DefaultLevelWrappers provides a default set of emoji wrappers for each log level,
 // commonly used by the Telegram adapter.
 var DefaultLevelWrappers = map[Level]string{
+	TraceLevel:   "🔬🔬",
+	DebugLevel:   "🐞🐞",
 	InfoLevel:    "ℹ️ℹ️",
 	WarningLevel: "⚠️⚠️",
 	ErrorLevel:   "‼️‼️",
@@ -33,6 +41,10 @@ var DefaultLevelWrappers = map[Level]string{
 // String returns the lower-case string representation of the log level.
 func (l Level) String() string {
 	switch l {
+	case TraceLevel:
+		return "trace"
+	case DebugLevel:
+		return "debug"
 	case InfoLevel:
 		return "info"
 	case WarningLevel:
@@ -49,6 +61,10 @@ func (l Level) String() string {
 // ToLogrus converts the internal Level to a logrus.Level.
 func (l Level) ToLogrus() logrus.Level {
 	switch l {
+	case TraceLevel:
+		return logrus.TraceLevel
+	case DebugLevel:
+		return logrus.DebugLevel
 	case InfoLevel:
 		return logrus.InfoLevel
 	case WarningLevel:
@@ -62,10 +78,54 @@ func (l Level) ToLogrus() logrus.Level {
 	}
 }
 
+// ToSlog converts the internal Level to a slog.Level.
+// Trace and Fatal, which slog does not define, map to slog.LevelDebug-4 and slog.LevelError+4.
+func (l Level) ToSlog() slog.Level {
+	switch l {
+	case TraceLevel:
+		return slog.LevelDebug - 4
+	case DebugLevel:
+		return slog.LevelDebug
+	case InfoLevel:
+		return slog.LevelInfo
+	case WarningLevel:
+		return slog.LevelWarn
+	case ErrorLevel:
+		return slog.LevelError
+	case FatalLevel:
+		return slog.LevelError + 4
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// LevelFromSlog converts a slog.Level to the closest internal Level.
+// Levels between the named slog levels round down, e.g. slog.LevelInfo+2 becomes InfoLevel.
+func LevelFromSlog(level slog.Level) Level {
+	switch {
+	case level < slog.LevelDebug:
+		return TraceLevel
+	case level < slog.LevelInfo:
+		return DebugLevel
+	case level < slog.LevelWarn:
+		return InfoLevel
+	case level < slog.LevelError:
+		return WarningLevel
+	case level < slog.LevelError+4:
+		return ErrorLevel
+	default:
+		return FatalLevel
+	}
+}
+
 // ParseLevel parses a string into a Level.
 // It returns an error if the string does not match any known log level.
 func ParseLevel(lvl string) (Level, error) {
 	switch strings.ToLower(lvl) {
+	case "trace":
+		return TraceLevel, nil
+	case "debug":
+		return DebugLevel, nil
 	case "info":
 		return InfoLevel, nil
 	case "warn", "warning":
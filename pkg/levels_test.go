@@ -1,6 +1,7 @@
 package composite_logger
 
 import (
+	"log/slog"
 	"testing"
 
 	"github.com/sirupsen/logrus"
@@ -13,6 +14,8 @@ func TestLevel_String(t *testing.T) {
 		level    Level
 		expected string
 	}{
+		{TraceLevel, "trace"},
+		{DebugLevel, "debug"},
 		{InfoLevel, "info"},
 		{WarningLevel, "warning"},
 		{ErrorLevel, "error"},
@@ -30,6 +33,8 @@ func TestLevel_ToLogrus(t *testing.T) {
 		level    Level
 		expected logrus.Level
 	}{
+		{TraceLevel, logrus.TraceLevel},
+		{DebugLevel, logrus.DebugLevel},
 		{InfoLevel, logrus.InfoLevel},
 		{WarningLevel, logrus.WarnLevel},
 		{ErrorLevel, logrus.ErrorLevel},
@@ -48,6 +53,9 @@ func TestParseLevel(t *testing.T) {
 		expected Level
 		wantErr  bool
 	}{
+		{"trace", TraceLevel, false},
+		{"debug", DebugLevel, false},
+		{"DEBUG", DebugLevel, false},
 		{"info", InfoLevel, false},
 		{"INFO", InfoLevel, false},
 		{"warn", WarningLevel, false},
@@ -69,3 +77,42 @@ func TestParseLevel(t *testing.T) {
 		})
 	}
 }
+
+func TestLevel_ToSlog(t *testing.T) {
+	tests := []struct {
+		level    Level
+		expected slog.Level
+	}{
+		{TraceLevel, slog.LevelDebug - 4},
+		{DebugLevel, slog.LevelDebug},
+		{InfoLevel, slog.LevelInfo},
+		{WarningLevel, slog.LevelWarn},
+		{ErrorLevel, slog.LevelError},
+		{FatalLevel, slog.LevelError + 4},
+		{Level(99), slog.LevelInfo},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, tt.level.ToSlog())
+	}
+}
+
+func TestLevelFromSlog(t *testing.T) {
+	tests := []struct {
+		level    slog.Level
+		expected Level
+	}{
+		{slog.LevelDebug - 4, TraceLevel},
+		{slog.LevelDebug, DebugLevel},
+		{slog.LevelInfo, InfoLevel},
+		{slog.LevelInfo + 2, InfoLevel},
+		{slog.LevelWarn, WarningLevel},
+		{slog.LevelError, ErrorLevel},
+		{slog.LevelError + 4, FatalLevel},
+		{slog.LevelError + 8, FatalLevel},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, LevelFromSlog(tt.level), tt.level.String())
+	}
+}
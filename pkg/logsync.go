@@ -0,0 +1,74 @@
+package composite_logger
+
+import "context"
+
+// LogSync logs the message and waits until every adapter has handled it.
+// It returns the result of each adapter keyed by adapter name (see AdapterSetting.Name):
+// nil if the adapter wrote the entry, or the reason it did not, e.g. ErrEntryDropped, ErrAdapterTimeout
+// or an error returned by an adapter implementing ports.CheckedLogger or CheckedEntryLogger.
+// Adapters that cannot report errors count as successful once their call returns.
+// A FatalLevel entry logged with LogSync never terminates the process.
+//
+// Usage:
+//
+//	results := logger.LogSync(composite_logger.InfoLevel, "payment captured", map[string]interface{}{"id": id})
+//	if err := results["audit"]; err != nil {
+//		return fmt.Errorf("audit log not confirmed: %w", err)
+//	}
+func (cl *CompositeLogger) LogSync(level Level, msg string, fields map[string]interface{}) map[string]error {
+	return cl.LogSyncContext(context.Background(), level, msg, fields)
+}
+
+// LogSyncContext is LogSync with the fields attached to ctx.
+// It stops waiting once ctx is done, including while the main queue is full; adapters that have not completed
+// by then report ctx.Err().
+func (cl *CompositeLogger) LogSyncContext(ctx context.Context, level Level, msg string, fields map[string]interface{}) map[string]error {
+	c, base := cl.resolve()
+	if c == nil {
+		return nil
+	}
+
+	entry := cl.prepare(base, newEntry(level, msg, contextFields(ctx, fields)))
+	entry.delivery = newDelivery(len(c.sinks))
+
+	if !c.enqueueContext(ctx, entry) {
+		return c.results(entry.delivery, ErrLoggerStopped)
+	}
+
+	select {
+	case <-entry.delivery.done:
+		// Adapters without a result never received the entry because the main queue dropped it,
+		// possibly because ctx was done while the queue was full.
+		if err := ctx.Err(); err != nil {
+			return c.results(entry.delivery, err)
+		}
+		return c.results(entry.delivery, ErrEntryDropped)
+	case <-ctx.Done():
+		return c.results(entry.delivery, ctx.Err())
+	}
+}
+
+// results collects the result of every adapter, using missing for adapters that have not completed.
+func (c *core) results(d *delivery, missing error) map[string]error {
+	results := make(map[string]error, len(c.sinks))
+	for _, s := range c.sinks {
+		if completed, err := d.result(s.name); completed {
+			results[s.name] = err
+		} else {
+			results[s.name] = missing
+		}
+	}
+
+	return results
+}
+
+// LogSync logs the message with the global logger and waits until every adapter has handled it.
+func LogSync(level Level, msg string, fields map[string]interface{}) map[string]error {
+	return Default().LogSync(level, msg, fields)
+}
+
+// LogSyncContext logs the message with the global logger and the fields attached to ctx,
+// and waits until every adapter has handled it or ctx is done.
+func LogSyncContext(ctx context.Context, level Level, msg string, fields map[string]interface{}) map[string]error {
+	return Default().LogSyncContext(ctx, level, msg, fields)
+}
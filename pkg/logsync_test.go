@@ -0,0 +1,153 @@
+package composite_logger
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// checkedLogger implements ports.CheckedLogger and fails every write with err.
+type checkedLogger struct {
+	fakeLogger
+	err error
+}
+
+func (c *checkedLogger) TryInfo(string, map[string]interface{}) error  { return c.err }
+func (c *checkedLogger) TryWarn(string, map[string]interface{}) error  { return c.err }
+func (c *checkedLogger) TryError(string, map[string]interface{}) error { return c.err }
+func (c *checkedLogger) TryFatal(string, map[string]interface{}) error { return c.err }
+
+// checkedVerboseLogger implements ports.CheckedVerboseLogger and fails every write with err.
+type checkedVerboseLogger struct {
+	checkedLogger
+}
+
+func (c *checkedVerboseLogger) Debug(string, map[string]interface{})          {}
+func (c *checkedVerboseLogger) Trace(string, map[string]interface{})          {}
+func (c *checkedVerboseLogger) TryDebug(string, map[string]interface{}) error { return c.err }
+func (c *checkedVerboseLogger) TryTrace(string, map[string]interface{}) error { return c.err }
+
+func TestLogSync_ChecksDebugAndTraceEntries(t *testing.T) {
+	writeErr := errors.New("disk full")
+	cl, err := NewWithOptions(Options{ErrorHandler: func(*AdapterError) {}},
+		AdapterSetting{Setting: testSetting{&checkedVerboseLogger{checkedLogger{err: writeErr}}}, Name: "verbose"},
+		AdapterSetting{Setting: testSetting{&checkedLogger{err: writeErr}}, Name: "checked"})
+	require.NoError(t, err)
+	defer cl.Stop()
+
+	for _, level := range []Level{DebugLevel, TraceLevel} {
+		results := cl.LogSync(level, "cache miss", nil)
+
+		assert.ErrorIs(t, results["verbose"], writeErr)
+		assert.NoError(t, results["checked"], "adapters without checked verbose methods are not checked")
+	}
+}
+
+func TestLogSync_ReturnsResultPerAdapter(t *testing.T) {
+	writeErr := errors.New("disk full")
+	recorder := &errorRecorder{}
+	l := &fakeLogger{}
+
+	cl, err := NewWithOptions(Options{ErrorHandler: recorder.handle},
+		AdapterSetting{Setting: testSetting{l}, Name: "console"},
+		AdapterSetting{Setting: testSetting{&checkedLogger{err: writeErr}}, Name: "audit"},
+		AdapterSetting{Setting: testSetting{panickingLogger{}}, Name: "broken"})
+	require.NoError(t, err)
+	defer cl.Stop()
+
+	results := cl.LogSync(InfoLevel, "payment captured", map[string]interface{}{"id": 7})
+
+	require.Len(t, results, 3)
+	assert.NoError(t, results["console"])
+	assert.ErrorIs(t, results["audit"], writeErr)
+	assert.ErrorIs(t, results["broken"], ErrAdapterPanic)
+	require.Len(t, l.infoCalls, 1, "LogSync must return after delivery")
+	assert.Equal(t, 7, l.infoCalls[0].context["id"])
+	assert.Len(t, recorder.errors, 2)
+}
+
+func TestLogSync_ReportsTimeout(t *testing.T) {
+	hung := blockingLogger{release: make(chan struct{})}
+	defer close(hung.release)
+
+	cl, err := NewWithOptions(Options{ErrorHandler: func(*AdapterError) {}},
+		AdapterSetting{Setting: testSetting{hung}, Timeout: 10 * time.Millisecond})
+	require.NoError(t, err)
+
+	results := cl.LogSync(WarningLevel, "slow", nil)
+
+	assert.ErrorIs(t, results["test"], ErrAdapterTimeout)
+}
+
+func TestLogSyncContext_StopsWaitingWhenContextIsDone(t *testing.T) {
+	hung := blockingLogger{release: make(chan struct{})}
+	defer close(hung.release)
+
+	cl, err := New(testSetting{hung})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	results := cl.LogSyncContext(ctx, InfoLevel, "unconfirmed", nil)
+
+	assert.ErrorIs(t, results["test"], context.DeadlineExceeded)
+}
+
+func TestLogSyncContext_StopsWaitingForRoomInFullQueue(t *testing.T) {
+	hung := blockingLogger{release: make(chan struct{})}
+	defer close(hung.release)
+
+	cl, err := NewWithOptions(Options{QueueSize: 1, DropReportInterval: -1},
+		AdapterSetting{Setting: testSetting{hung}, QueueSize: 1, OverflowPolicy: OverflowBlock})
+	require.NoError(t, err)
+
+	go func() {
+		for i := 0; i < 5; i++ {
+			cl.Info("burst", nil)
+		}
+	}()
+	require.Eventually(t, func() bool { return len(cl.core.queue.ch) == 1 }, time.Second, time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	done := make(chan map[string]error, 1)
+	go func() { done <- cl.LogSyncContext(ctx, InfoLevel, "unconfirmed", nil) }()
+
+	select {
+	case results := <-done:
+		assert.ErrorIs(t, results["test"], context.DeadlineExceeded)
+	case <-time.After(time.Second):
+		t.Fatal("LogSyncContext waited for room in the full queue beyond ctx")
+	}
+}
+
+func TestLogSync_StoppedLogger(t *testing.T) {
+	cl, err := New(testSetting{&fakeLogger{}})
+	require.NoError(t, err)
+	cl.Stop()
+
+	results := cl.LogSync(InfoLevel, "too late", nil)
+
+	assert.Equal(t, map[string]error{"test": ErrLoggerStopped}, results)
+}
+
+func TestLogSync_FatalDoesNotExit(t *testing.T) {
+	l := &fakeLogger{}
+	cl, err := NewWithOptions(Options{
+		ExitOnFatal: true,
+		ExitFunc:    func(int) { t.Fatal("LogSync must not exit") },
+	}, testSetting{l})
+	require.NoError(t, err)
+	defer cl.Stop()
+
+	results := cl.LogSync(FatalLevel, "confirmed", nil)
+
+	assert.NoError(t, results["test"])
+	assert.Len(t, l.fatalCalls, 1)
+}
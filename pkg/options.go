@@ -0,0 +1,112 @@
+package composite_logger
+
+import (
+	"os"
+	"time"
+)
+
+const (
+	// defaultDropReportInterval is how often dropped entries are reported when no interval is configured.
+	defaultDropReportInterval = time.Minute
+	// defaultFatalTimeout bounds the delivery of a fatal entry and the flushing of the adapters when no timeout is configured.
+	defaultFatalTimeout = 5 * time.Second
+)
+
+// Options configures the behaviour of a CompositeLogger beyond its adapter settings.
+// The zero value is ready to use.
+//
+// Usage:
+//
+//	composite_logger.InitWithOptions(composite_logger.Options{
+//		QueueSize:      5000,
+//		OverflowPolicy: composite_logger.OverflowDropOldest,
+//	}, setting.ConsoleSetting{Enabled: true})
+type Options struct {
+	// QueueSize is the capacity of the main log queue (default: 1000).
+	QueueSize int
+	// OverflowPolicy decides what a log call does when the main queue is full (default: OverflowBlock).
+	OverflowPolicy OverflowPolicy
+	// BlockTimeout bounds the wait of OverflowBlockWithTimeout (default: 100ms).
+	BlockTimeout time.Duration
+	// DropReportInterval is how often a warning about dropped, sampled out and rate limited entries is logged
+	// (default: 1 minute). A negative value disables the report.
+	DropReportInterval time.Duration
+	// LegacyLevelPrefix restores the "[INFO] " style prefix in messages for every adapter
+	// without its own AdapterSetting.MessageDecorator.
+	LegacyLevelPrefix bool
+	// ExitOnFatal makes Fatal block until every adapter has delivered the entry, flush and close the adapters
+	// and then terminate the process.
+	// By default Fatal only logs and the application keeps running.
+	ExitOnFatal bool
+	// FatalTimeout bounds the delivery of a fatal entry and the flushing of the adapters before exiting (default: 5 seconds).
+	FatalTimeout time.Duration
+	// ExitFunc terminates the process after a fatal entry with exit code 1 (default: os.Exit).
+	// Tests can replace it to observe the exit.
+	ExitFunc func(code int)
+	// ErrorHandler receives adapter failures: recovered panics, timeouts and errors reported by the adapters themselves
+	// (default: print to standard error).
+	ErrorHandler ErrorHandler
+	// QuarantineAfter disables an adapter after this many consecutive panics. Zero keeps panicking adapters enabled.
+	QuarantineAfter int
+	// Processors transform every entry, in order, before it is handed to the adapters.
+	// See Processor.
+	Processors []Processor
+	// Redaction removes sensitive data from every entry before Processors and adapters see it.
+	// See RedactionRule and DefaultRedactionRules.
+	Redaction []RedactionRule
+	// Sampling limits repeated entries before they reach any adapter. Nil disables sampling.
+	Sampling *Sampling
+	// Routes send matching entries to specific adapters only. By default every entry goes to every adapter.
+	// See Route.
+	Routes []Route
+}
+
+func (o Options) overflowPolicy() OverflowPolicy {
+	if o.OverflowPolicy == 0 {
+		return OverflowBlock
+	}
+
+	return o.OverflowPolicy
+}
+
+func (o Options) dropReportInterval() time.Duration {
+	if o.DropReportInterval == 0 {
+		return defaultDropReportInterval
+	}
+
+	return o.DropReportInterval
+}
+
+func (o Options) fatalTimeout() time.Duration {
+	if o.FatalTimeout <= 0 {
+		return defaultFatalTimeout
+	}
+
+	return o.FatalTimeout
+}
+
+func (o Options) exitFunc() func(code int) {
+	if o.ExitFunc == nil {
+		return os.Exit
+	}
+
+	return o.ExitFunc
+}
+
+func (o Options) errorHandler() ErrorHandler {
+	if o.ErrorHandler == nil {
+		return defaultErrorHandler
+	}
+
+	return o.ErrorHandler
+}
+
+// adapterSetting returns the delivery options for s with the logger-wide defaults applied.
+func (o Options) adapterSetting(s LoggerSetting) AdapterSetting {
+	as := adapterSetting(s)
+	if as.MessageDecorator == nil && o.LegacyLevelPrefix {
+		as.MessageDecorator = LevelPrefixDecorator
+	}
+
+	return as
+}
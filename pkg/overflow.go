@@ -0,0 +1,176 @@
+package composite_logger
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// OverflowPolicy defines what happens to a new entry when a queue is full.
+type OverflowPolicy int
+
+const (
+	// OverflowBlock waits until the queue has room. It is the default for the main log queue.
+	OverflowBlock OverflowPolicy = iota + 1
+	// OverflowDropNewest discards the entry that does not fit. It is the default for adapter queues.
+	OverflowDropNewest
+	// OverflowDropOldest discards the oldest queued entry to make room for the new one.
+	OverflowDropOldest
+	// OverflowBlockWithTimeout waits for room up to a timeout and then discards the new entry.
+	OverflowBlockWithTimeout
+)
+
+// defaultBlockTimeout is used by OverflowBlockWithTimeout when no timeout is configured.
+const defaultBlockTimeout = 100 * time.Millisecond
+
+// queue is a bounded channel of entries with an overflow policy and a counter of discarded entries.
+type queue struct {
+	ch           chan Entry
+	policy       OverflowPolicy
+	blockTimeout time.Duration
+	dropped      atomic.Uint64
+	// pushed and taken count the entries put into and removed from the queue, including evicted ones,
+	// so adapter workers can tell when the entries queued before a flush request are gone.
+	pushed atomic.Uint64
+	taken  atomic.Uint64
+	// release is called with the delivery of every discarded entry, so waiting callers are not left hanging.
+	release func(d *delivery)
+	// abort, once closed, makes blocking pushes discard their entries instead of waiting. Nil never aborts.
+	abort <-chan struct{}
+}
+
+func newQueue(size int, policy OverflowPolicy, blockTimeout time.Duration, release func(d *delivery)) *queue {
+	if size <= 0 {
+		size = defaultQueueSize
+	}
+	if blockTimeout <= 0 {
+		blockTimeout = defaultBlockTimeout
+	}
+
+	return &queue{
+		ch:           make(chan Entry, size),
+		policy:       policy,
+		blockTimeout: blockTimeout,
+		release:      release,
+	}
+}
+
+// push puts the entry into the queue according to the overflow policy.
+// Flush barriers wait for room regardless of the policy until their flush context is done.
+// Blocking policies give up and discard the entry once the queue is aborted.
+func (q *queue) push(entry Entry) {
+	q.pushUntil(entry, nil)
+}
+
+// pushUntil is push for callers with a deadline: blocking policies also give up and discard the entry
+// once done is closed.
+func (q *queue) pushUntil(entry Entry, done <-chan struct{}) {
+	if q.send(entry, done) {
+		q.pushed.Add(1)
+	}
+}
+
+// send puts the entry into the queue according to the overflow policy and reports whether it was queued.
+func (q *queue) send(entry Entry, done <-chan struct{}) bool {
+	if entry.barrier != nil {
+		select {
+		case q.ch <- entry:
+			return true
+		case <-entry.barrier.Done():
+			return false
+		case <-q.abort:
+			q.drop(entry)
+			return false
+		}
+	}
+
+	switch q.policy {
+	case OverflowDropNewest:
+		select {
+		case q.ch <- entry:
+			return true
+		default:
+			q.drop(entry)
+			return false
+		}
+	case OverflowDropOldest:
+		for {
+			select {
+			case q.ch <- entry:
+				return true
+			default:
+			}
+
+			select {
+			case oldest := <-q.ch:
+				q.taken.Add(1)
+				q.drop(oldest)
+			default:
+			}
+		}
+	case OverflowBlockWithTimeout:
+		select {
+		case q.ch <- entry:
+			return true
+		default:
+		}
+
+		timer := time.NewTimer(q.blockTimeout)
+		defer timer.Stop()
+
+		select {
+		case q.ch <- entry:
+			return true
+		case <-timer.C:
+			q.drop(entry)
+			return false
+		case <-q.abort:
+			q.drop(entry)
+			return false
+		case <-done:
+			q.drop(entry)
+			return false
+		}
+	default:
+		select {
+		case q.ch <- entry:
+			return true
+		default:
+		}
+
+		select {
+		case q.ch <- entry:
+			return true
+		case <-q.abort:
+			q.drop(entry)
+			return false
+		case <-done:
+			q.drop(entry)
+			return false
+		}
+	}
+}
+
+// tryPush puts the entry into the queue if it has room, regardless of the overflow policy, and reports whether it did.
+func (q *queue) tryPush(entry Entry) bool {
+	select {
+	case q.ch <- entry:
+		q.pushed.Add(1)
+		return true
+	default:
+		return false
+	}
+}
+
+// drop counts the discarded entry and releases its delivery.
+// An evicted flush barrier is not counted and completes at once: every entry queued before it has been evicted as well.
+func (q *queue) drop(entry Entry) {
+	if entry.barrier != nil {
+		entry.delivery.finish()
+		return
+	}
+
+	q.dropped.Add(1)
+	if entry.delivery != nil {
+		q.release(entry.delivery)
+	}
+}
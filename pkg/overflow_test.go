@@ -0,0 +1,97 @@
+package composite_logger
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueue_DropNewest(t *testing.T) {
+	q := newQueue(1, OverflowDropNewest, 0, (*delivery).ack)
+
+	q.push(Entry{Message: "first"})
+	q.push(Entry{Message: "second"})
+
+	require.Len(t, q.ch, 1)
+	assert.Equal(t, "first", (<-q.ch).Message)
+	assert.Equal(t, uint64(1), q.dropped.Load())
+}
+
+func TestQueue_DropOldest(t *testing.T) {
+	q := newQueue(2, OverflowDropOldest, 0, (*delivery).ack)
+
+	q.push(Entry{Message: "first"})
+	q.push(Entry{Message: "second"})
+	q.push(Entry{Message: "third"})
+
+	require.Len(t, q.ch, 2)
+	assert.Equal(t, "second", (<-q.ch).Message)
+	assert.Equal(t, "third", (<-q.ch).Message)
+	assert.Equal(t, uint64(1), q.dropped.Load())
+}
+
+func TestQueue_BlockWithTimeout(t *testing.T) {
+	q := newQueue(1, OverflowBlockWithTimeout, 10*time.Millisecond, (*delivery).ack)
+
+	q.push(Entry{Message: "first"})
+	start := time.Now()
+	q.push(Entry{Message: "second"})
+
+	assert.GreaterOrEqual(t, time.Since(start), 10*time.Millisecond)
+	assert.Len(t, q.ch, 1)
+	assert.Equal(t, uint64(1), q.dropped.Load())
+}
+
+func TestQueue_BlockWaitsForRoom(t *testing.T) {
+	q := newQueue(1, OverflowBlock, 0, (*delivery).ack)
+	q.push(Entry{Message: "first"})
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		<-q.ch
+	}()
+	q.push(Entry{Message: "second"})
+
+	assert.Equal(t, "second", (<-q.ch).Message)
+	assert.Zero(t, q.dropped.Load())
+}
+
+func TestNewQueue_Defaults(t *testing.T) {
+	q := newQueue(0, OverflowBlock, 0, (*delivery).ack)
+
+	assert.Equal(t, defaultQueueSize, cap(q.ch))
+	assert.Equal(t, defaultBlockTimeout, q.blockTimeout)
+}
+
+func TestCompositeLogger_ReportsDroppedEntries(t *testing.T) {
+	slow := blockingLogger{release: make(chan struct{})}
+	observer := &fakeLogger{}
+
+	cl, err := NewWithOptions(Options{DropReportInterval: -1},
+		AdapterSetting{Setting: testSetting{slow}, QueueSize: 1, OverflowPolicy: OverflowDropNewest},
+		AdapterSetting{Setting: testSetting{observer}, QueueSize: 100},
+	)
+	require.NoError(t, err)
+
+	for i := 0; i < 5; i++ {
+		cl.Info("burst", nil)
+	}
+	assert.Eventually(t, func() bool { return cl.Dropped() >= 3 }, time.Second, time.Millisecond)
+
+	close(slow.release)
+	cl.Stop()
+
+	require.NotEmpty(t, observer.warnCalls)
+	report := observer.warnCalls[len(observer.warnCalls)-1]
+	assert.Contains(t, report.message, "log entries dropped")
+	assert.GreaterOrEqual(t, report.context["total_dropped"], uint64(3))
+}
+
+func TestOptions_Defaults(t *testing.T) {
+	assert.Equal(t, OverflowBlock, Options{}.overflowPolicy())
+	assert.Equal(t, defaultDropReportInterval, Options{}.dropReportInterval())
+	assert.Equal(t, OverflowDropNewest, AdapterSetting{}.overflowPolicy())
+	assert.Equal(t, OverflowDropOldest, Options{OverflowPolicy: OverflowDropOldest}.overflowPolicy())
+}
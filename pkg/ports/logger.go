@@ -1,5 +1,7 @@
 package ports
 
+import "context"
+
 // Logger defines the interface for all logging adapters.
 type Logger interface {
 	// Info logs a message with informational severity.
@@ -11,3 +13,53 @@ type Logger interface {
 	// Fatal logs a message with fatal severity and may lead to process termination.
 	Fatal(message string, context map[string]interface{})
 }
+
+// VerboseLogger is an optional extension of Logger for adapters that accept debug and trace messages.
+// Adapters implementing only Logger keep working unchanged; they simply do not receive entries below the info level.
+type VerboseLogger interface {
+	Logger
+	// Debug logs a message with debug severity.
+	Debug(message string, context map[string]interface{})
+	// Trace logs a message with trace severity.
+	Trace(message string, context map[string]interface{})
+}
+
+// CheckedLogger is an optional extension of Logger for adapters that can tell whether a message was written,
+// e.g. whether a remote API accepted it. The core prefers these methods when they are available.
+type CheckedLogger interface {
+	Logger
+	// TryInfo logs a message with informational severity and returns the write error, if any.
+	TryInfo(message string, context map[string]interface{}) error
+	// TryWarn logs a message with warning severity and returns the write error, if any.
+	TryWarn(message string, context map[string]interface{}) error
+	// TryError logs a message with error severity and returns the write error, if any.
+	TryError(message string, context map[string]interface{}) error
+	// TryFatal logs a message with fatal severity and returns the write error, if any.
+	TryFatal(message string, context map[string]interface{}) error
+}
+
+// CheckedVerboseLogger is an optional extension of CheckedLogger for adapters that accept debug and trace messages
+// and can tell whether they were written. Debug and trace messages of adapters implementing only CheckedLogger
+// are not checked.
+type CheckedVerboseLogger interface {
+	CheckedLogger
+	VerboseLogger
+	// TryDebug logs a message with debug severity and returns the write error, if any.
+	TryDebug(message string, context map[string]interface{}) error
+	// TryTrace logs a message with trace severity and returns the write error, if any.
+	TryTrace(message string, context map[string]interface{}) error
+}
+
+// Flusher is an optional extension of Logger for adapters that buffer messages.
+// Flush is called after every message queued before CompositeLogger.Flush has been handled.
+type Flusher interface {
+	// Flush writes buffered messages, giving up when ctx is done.
+	Flush(ctx context.Context) error
+}
+
+// Closer is an optional extension of Logger for adapters that hold resources such as file handles.
+// Close is called once during shutdown, after the adapter has handled its last message.
+type Closer interface {
+	// Close releases the adapter resources, giving up when ctx is done.
+	Close(ctx context.Context) error
+}
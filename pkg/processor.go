@@ -0,0 +1,142 @@
+package composite_logger
+
+import (
+	"fmt"
+	"os"
+	"runtime/debug"
+
+	"github.com/Consolushka/golang.composite_logger/internal"
+)
+
+// Field keys used by the bundled processors.
+const (
+	HostnameKey    = "hostname"
+	PIDKey         = "pid"
+	ServiceKey     = "service"
+	VersionKey     = "version"
+	EnvironmentKey = "environment"
+)
+
+// Processor transforms an entry on its way to the adapters. It returns the entry to pass on,
+// or false to drop it. Processors must not modify entry.Fields in place, because the map is shared
+// with the caller and the other adapters; use Entry.WithFields to add fields.
+//
+// Processors set in Options.Processors run once per entry on the dispatcher goroutine before fan-out,
+// so they should be fast. Processors set in AdapterSetting.Processors run on the adapter's worker
+// and only affect that adapter.
+//
+// Usage:
+//
+//	func dropHealthChecks(entry composite_logger.Entry) (composite_logger.Entry, bool) {
+//		return entry, entry.Fields["path"] != "/healthz"
+//	}
+type Processor func(entry Entry) (Entry, bool)
+
+// WithFields returns a copy of the entry with the given fields added; they take precedence over existing ones.
+func (e Entry) WithFields(fields map[string]interface{}) Entry {
+	e.Fields = internal.MergeContext(e.Fields, fields)
+	return e
+}
+
+// process runs the processors in order until one of them drops the entry.
+// If a processor panics, the remaining processors are skipped and the entry is kept as it was before the panicking one.
+func process(processors []Processor, entry Entry) (result Entry, keep bool, err error) {
+	for _, p := range processors {
+		next, ok, panicErr := runProcessor(p, entry)
+		if panicErr != nil {
+			return entry, true, panicErr
+		}
+		if !ok {
+			return entry, false, nil
+		}
+		entry = next
+	}
+
+	return entry, true, nil
+}
+
+func runProcessor(p Processor, entry Entry) (result Entry, keep bool, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", ErrProcessorPanic, r)
+		}
+	}()
+
+	result, keep = p(entry)
+	return result, keep, nil
+}
+
+// Fields returns a processor adding the given fields to every entry.
+// Fields passed to a log call take precedence.
+//
+// Usage:
+//
+//	composite_logger.Fields(map[string]interface{}{"region": "eu-west-1"})
+func Fields(fields map[string]interface{}) Processor {
+	fields = internal.CloneContext(fields)
+
+	return func(entry Entry) (Entry, bool) {
+		entry.Fields = internal.MergeContext(fields, entry.Fields)
+		return entry, true
+	}
+}
+
+// Hostname returns a processor adding the host name under the "hostname" key.
+// The name is looked up once; if it cannot be determined the processor adds nothing.
+func Hostname() Processor {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return passThrough
+	}
+
+	return Fields(map[string]interface{}{HostnameKey: hostname})
+}
+
+// PID returns a processor adding the process ID under the "pid" key.
+func PID() Processor {
+	return Fields(map[string]interface{}{PIDKey: os.Getpid()})
+}
+
+// Service returns a processor adding the service name under the "service" key.
+func Service(name string) Processor {
+	return Fields(map[string]interface{}{ServiceKey: name})
+}
+
+// Environment returns a processor adding the deployment environment, e.g. "production", under the "environment" key.
+func Environment(env string) Processor {
+	return Fields(map[string]interface{}{EnvironmentKey: env})
+}
+
+// Version returns a processor adding the version of the main module under the "version" key.
+// It is read from debug.ReadBuildInfo: the module version for binaries built with "go install module@version",
+// otherwise the VCS revision stamped by "go build". If neither is known the processor adds nothing.
+func Version() Processor {
+	version := buildVersion()
+	if version == "" {
+		return passThrough
+	}
+
+	return Fields(map[string]interface{}{VersionKey: version})
+}
+
+func buildVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	if info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+
+	for _, s := range info.Settings {
+		if s.Key == "vcs.revision" {
+			return s.Value
+		}
+	}
+
+	return ""
+}
+
+func passThrough(entry Entry) (Entry, bool) {
+	return entry, true
+}
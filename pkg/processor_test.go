@@ -0,0 +1,125 @@
+package composite_logger
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessors_EnrichEveryEntry(t *testing.T) {
+	l := &fakeLogger{}
+	cl, err := NewWithOptions(Options{
+		Processors: []Processor{Service("billing"), Environment("production"), PID()},
+	}, testSetting{l})
+	require.NoError(t, err)
+
+	ctx := map[string]interface{}{"service": "override"}
+	cl.Info("charged", ctx)
+	cl.Stop()
+
+	require.Len(t, l.infoCalls, 1)
+	fields := l.infoCalls[0].context
+	assert.Equal(t, "override", fields[ServiceKey], "call fields take precedence")
+	assert.Equal(t, "production", fields[EnvironmentKey])
+	assert.Equal(t, os.Getpid(), fields[PIDKey])
+	assert.Len(t, ctx, 1, "the caller's map is not modified")
+}
+
+func TestProcessors_RunInOrderAndCanRewriteMessages(t *testing.T) {
+	l := &fakeLogger{}
+	upper := func(entry Entry) (Entry, bool) {
+		entry.Message = strings.ToUpper(entry.Message)
+		return entry, true
+	}
+	suffix := func(entry Entry) (Entry, bool) {
+		entry.Message += "!"
+		return entry, true
+	}
+
+	cl, err := NewWithOptions(Options{Processors: []Processor{upper, suffix}}, testSetting{l})
+	require.NoError(t, err)
+
+	cl.Warn("disk almost full", nil)
+	cl.Stop()
+
+	require.Len(t, l.warnCalls, 1)
+	assert.Equal(t, "DISK ALMOST FULL!", l.warnCalls[0].message)
+}
+
+func TestProcessors_DropEntries(t *testing.T) {
+	l := &fakeLogger{}
+	dropHealthChecks := func(entry Entry) (Entry, bool) {
+		return entry, entry.Fields["path"] != "/healthz"
+	}
+
+	cl, err := NewWithOptions(Options{Processors: []Processor{dropHealthChecks}}, testSetting{l})
+	require.NoError(t, err)
+
+	cl.Info("request", map[string]interface{}{"path": "/healthz"})
+	cl.Info("request", map[string]interface{}{"path": "/orders"})
+	results := cl.LogSync(InfoLevel, "request", map[string]interface{}{"path": "/healthz"})
+	cl.Stop()
+
+	require.Len(t, l.infoCalls, 1)
+	assert.Equal(t, "/orders", l.infoCalls[0].context["path"])
+	assert.ErrorIs(t, results["test"], ErrEntryFiltered)
+}
+
+func TestProcessors_PerAdapter(t *testing.T) {
+	console := &fakeLogger{}
+	telegram := &fakeLogger{}
+	errorsOnly := func(entry Entry) (Entry, bool) {
+		return entry.WithFields(map[string]interface{}{"alert": true}), entry.Level >= ErrorLevel
+	}
+
+	cl, err := New(
+		AdapterSetting{Setting: testSetting{console}, Name: "console"},
+		AdapterSetting{Setting: testSetting{telegram}, Name: "telegram", Processors: []Processor{errorsOnly}},
+	)
+	require.NoError(t, err)
+
+	cl.Info("started", nil)
+	cl.Error("failed", nil)
+	cl.Stop()
+
+	assert.Len(t, console.infoCalls, 1)
+	require.Len(t, console.errorCalls, 1)
+	assert.NotContains(t, console.errorCalls[0].context, "alert")
+	assert.Empty(t, telegram.infoCalls)
+	require.Len(t, telegram.errorCalls, 1)
+	assert.Equal(t, true, telegram.errorCalls[0].context["alert"])
+}
+
+func TestProcessors_PanicIsReported(t *testing.T) {
+	l := &fakeLogger{}
+	recorder := &errorRecorder{}
+	broken := func(Entry) (Entry, bool) { panic("boom") }
+
+	cl, err := NewWithOptions(Options{
+		Processors:   []Processor{Service("billing"), broken},
+		ErrorHandler: recorder.handle,
+	}, testSetting{l})
+	require.NoError(t, err)
+
+	cl.Info("still delivered", nil)
+	cl.Stop()
+
+	require.Len(t, l.infoCalls, 1)
+	assert.Equal(t, "billing", l.infoCalls[0].context[ServiceKey])
+	require.Len(t, recorder.errors, 1)
+	assert.ErrorIs(t, recorder.errors[0], ErrProcessorPanic)
+	assert.EqualError(t, recorder.errors[0], "composite_logger: processor panicked: boom")
+}
+
+func TestHostname(t *testing.T) {
+	hostname, err := os.Hostname()
+	require.NoError(t, err)
+
+	entry, keep := Hostname()(Entry{})
+
+	assert.True(t, keep)
+	assert.Equal(t, hostname, entry.Fields[HostnameKey])
+}
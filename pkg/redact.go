@@ -0,0 +1,281 @@
+package composite_logger
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// RedactAction decides how a sensitive value is replaced.
+type RedactAction int
+
+const (
+	// RedactMask replaces the value with "[REDACTED]". It is the default.
+	RedactMask RedactAction = iota + 1
+	// RedactHash replaces the value with "sha256:" and the first 16 hex digits of its SHA-256 hash,
+	// so equal values can still be correlated. Low-entropy values such as card numbers can be brute-forced
+	// from an unsalted hash; prefer RedactMask for them.
+	RedactHash
+	// RedactRemove deletes matched fields and cuts matched substrings out of strings.
+	RedactRemove
+)
+
+// RedactedPlaceholder replaces values redacted with RedactMask.
+const RedactedPlaceholder = "[REDACTED]"
+
+// RedactionRule describes sensitive data to redact from entries before any adapter sees them.
+// A rule matches fields by key, string contents by pattern, or both.
+//
+// Usage:
+//
+//	composite_logger.InitWithOptions(composite_logger.Options{
+//		Redaction: []composite_logger.RedactionRule{
+//			composite_logger.RedactKeys(composite_logger.RedactMask, "password", "headers.authorization"),
+//			composite_logger.RedactCardNumbers(composite_logger.RedactMask),
+//			composite_logger.RedactEmails(composite_logger.RedactHash),
+//		},
+//	}, setting.ConsoleSetting{Enabled: true})
+type RedactionRule struct {
+	// Keys are field names matched case-insensitively. A name without dots matches the field at any depth
+	// of nested maps, a dotted path such as "headers.authorization" matches only from the top level.
+	// The whole value of a matched field is redacted.
+	Keys []string
+	// Pattern matches sensitive substrings of the message, the stack trace, string fields and error messages.
+	Pattern *regexp.Regexp
+	// Validate confirms a Pattern match, e.g. with a checksum. Nil accepts every match.
+	Validate func(match string) bool
+	// Action is applied to matches (default: RedactMask).
+	Action RedactAction
+}
+
+var (
+	cardNumberPattern = regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`)
+	jwtPattern        = regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)
+	emailPattern      = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+)
+
+// RedactKeys returns a rule redacting the fields with the given names or dotted paths.
+func RedactKeys(action RedactAction, keys ...string) RedactionRule {
+	return RedactionRule{Keys: keys, Action: action}
+}
+
+// RedactCardNumbers returns a rule redacting payment card numbers (PAN) of 13 to 19 digits,
+// optionally separated by spaces or dashes, that pass the Luhn check.
+func RedactCardNumbers(action RedactAction) RedactionRule {
+	return RedactionRule{Pattern: cardNumberPattern, Validate: luhnValid, Action: action}
+}
+
+// RedactJWTs returns a rule redacting JSON Web Tokens.
+func RedactJWTs(action RedactAction) RedactionRule {
+	return RedactionRule{Pattern: jwtPattern, Action: action}
+}
+
+// RedactEmails returns a rule redacting e-mail addresses.
+func RedactEmails(action RedactAction) RedactionRule {
+	return RedactionRule{Pattern: emailPattern, Action: action}
+}
+
+// DefaultRedactionRules masks common credential fields (passwords, secrets, tokens, API keys,
+// Authorization and Cookie headers), card numbers and JWTs.
+func DefaultRedactionRules() []RedactionRule {
+	return []RedactionRule{
+		RedactKeys(RedactMask, "password", "passwd", "secret", "token", "access_token", "refresh_token",
+			"api_key", "apikey", "authorization", "cookie", "set-cookie"),
+		RedactCardNumbers(RedactMask),
+		RedactJWTs(RedactMask),
+	}
+}
+
+// luhnValid reports whether the digits of s pass the Luhn checksum; separators are ignored.
+func luhnValid(s string) bool {
+	sum, digits := 0, 0
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+
+		d := int(c - '0')
+		if digits%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		digits++
+	}
+
+	return digits >= 13 && digits <= 19 && sum%10 == 0
+}
+
+// redactor applies redaction rules to entries.
+type redactor struct {
+	names    map[string]RedactAction
+	paths    map[string]RedactAction
+	patterns []RedactionRule
+}
+
+func newRedactor(rules []RedactionRule) *redactor {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	r := &redactor{names: make(map[string]RedactAction), paths: make(map[string]RedactAction)}
+	for _, rule := range rules {
+		if rule.Action == 0 {
+			rule.Action = RedactMask
+		}
+		for _, key := range rule.Keys {
+			key = strings.ToLower(key)
+			if strings.Contains(key, ".") {
+				r.paths[key] = rule.Action
+			} else {
+				r.names[key] = rule.Action
+			}
+		}
+		if rule.Pattern != nil {
+			r.patterns = append(r.patterns, rule)
+		}
+	}
+
+	return r
+}
+
+// redact returns a copy of the entry with the message, stack trace and fields redacted.
+// The caller's fields map is never modified.
+func (r *redactor) redact(entry Entry) Entry {
+	entry.Message = r.redactString(entry.Message)
+	entry.Stack = r.redactString(entry.Stack)
+	if entry.Fields != nil {
+		entry.Fields = r.redactMap(entry.Fields, "")
+	}
+
+	return entry
+}
+
+// redactKeys returns a copy of the counts with their keys redacted. Counts of keys that become equal are added up.
+func (r *redactor) redactKeys(counts map[string]interface{}) map[string]interface{} {
+	redacted := make(map[string]interface{}, len(counts))
+	for key, count := range counts {
+		key = r.redactString(key)
+		if previous, ok := redacted[key].(uint64); ok {
+			if n, ok := count.(uint64); ok {
+				count = previous + n
+			}
+		}
+		redacted[key] = count
+	}
+
+	return redacted
+}
+
+func (r *redactor) redactMap(fields map[string]interface{}, prefix string) map[string]interface{} {
+	redacted := make(map[string]interface{}, len(fields))
+	for key, value := range fields {
+		path := strings.ToLower(key)
+		if prefix != "" {
+			path = prefix + "." + path
+		}
+
+		if action, ok := r.keyAction(key, path); ok {
+			if action != RedactRemove {
+				redacted[key] = replacement(action, fmt.Sprint(value))
+			}
+			continue
+		}
+
+		redacted[key] = r.redactValue(value, path)
+	}
+
+	return redacted
+}
+
+func (r *redactor) keyAction(key, path string) (RedactAction, bool) {
+	if action, ok := r.paths[path]; ok {
+		return action, true
+	}
+
+	action, ok := r.names[strings.ToLower(key)]
+	return action, ok
+}
+
+func (r *redactor) redactValue(value interface{}, path string) interface{} {
+	switch typed := value.(type) {
+	case string:
+		return r.redactString(typed)
+	case error:
+		if message := typed.Error(); r.redactString(message) != message {
+			return redactedError{message: r.redactString(message), err: typed}
+		}
+		return typed
+	case map[string]interface{}:
+		return r.redactMap(typed, path)
+	case map[string]string:
+		redacted := make(map[string]interface{}, len(typed))
+		for key, v := range typed {
+			redacted[key] = v
+		}
+		return r.redactMap(redacted, path)
+	case []interface{}:
+		redacted := make([]interface{}, len(typed))
+		for i, v := range typed {
+			redacted[i] = r.redactValue(v, path)
+		}
+		return redacted
+	case []string:
+		redacted := make([]string, len(typed))
+		for i, v := range typed {
+			redacted[i] = r.redactString(v)
+		}
+		return redacted
+	default:
+		return value
+	}
+}
+
+func (r *redactor) redactString(s string) string {
+	if s == "" {
+		return s
+	}
+
+	for _, rule := range r.patterns {
+		s = rule.Pattern.ReplaceAllStringFunc(s, func(match string) string {
+			if rule.Validate != nil && !rule.Validate(match) {
+				return match
+			}
+			return replacement(rule.Action, match)
+		})
+	}
+
+	return s
+}
+
+func replacement(action RedactAction, value string) string {
+	switch action {
+	case RedactHash:
+		sum := sha256.Sum256([]byte(value))
+		return "sha256:" + hex.EncodeToString(sum[:8])
+	case RedactRemove:
+		return ""
+	default:
+		return RedactedPlaceholder
+	}
+}
+
+// redactedError replaces an error whose message contained sensitive data.
+// The original error stays reachable through errors.Is and errors.As.
+type redactedError struct {
+	message string
+	err     error
+}
+
+func (e redactedError) Error() string {
+	return e.message
+}
+
+func (e redactedError) Unwrap() error {
+	return e.err
+}
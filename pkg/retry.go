@@ -0,0 +1,391 @@
+package composite_logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/Consolushka/golang.composite_logger/pkg/ports"
+)
+
+const (
+	// defaultRetryAttempts is the number of delivery attempts when none is configured.
+	defaultRetryAttempts = 3
+	// defaultRetryBackoff is the wait before the first retry when none is configured.
+	defaultRetryBackoff = 100 * time.Millisecond
+	// defaultRetryMaxBackoff caps the wait between retries when no cap is configured.
+	defaultRetryMaxBackoff = 5 * time.Second
+	// defaultBreakerThreshold is the number of consecutive failed deliveries that opens the circuit when none is configured.
+	defaultBreakerThreshold = 5
+	// defaultBreakerCooldown is how long the circuit stays open when no cooldown is configured.
+	defaultBreakerCooldown = 30 * time.Second
+)
+
+// CircuitState is the state of the circuit breaker of a RetrySetting adapter.
+type CircuitState int
+
+const (
+	// CircuitNone is reported for adapters without a circuit breaker.
+	CircuitNone CircuitState = iota
+	// CircuitClosed means entries are delivered normally.
+	CircuitClosed
+	// CircuitOpen means entries are rejected with ErrCircuitOpen without calling the adapter.
+	CircuitOpen
+	// CircuitHalfOpen means the cooldown has passed and the next entry probes whether the adapter recovered.
+	CircuitHalfOpen
+)
+
+// String returns the lower-case name of the state, e.g. "half-open".
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "none"
+	}
+}
+
+// RetrySetting wraps an adapter with bounded retries and a circuit breaker, for destinations with transient failures.
+// A delivery failing with a transient error is retried up to MaxAttempts times in total, waiting between attempts
+// with exponential backoff and jitter, or as long as the error asks through RetryAfterError if that is longer.
+// After BreakerThreshold consecutive failed deliveries the circuit opens: entries are rejected with
+// ErrCircuitOpen for BreakerCooldown, then a single entry probes the adapter with one attempt and closes
+// the circuit again on success. The state is reported by Health.
+//
+// Failures are errors of adapters implementing CheckedEntryLogger or ports.CheckedLogger, such as the Telegram
+// adapter without a send queue, and panics. Retryable decides which of them are retried; the others are returned
+// at once and do not count towards the breaker, since the destination answered. Retries run on the adapter's
+// worker, so AdapterSetting.Timeout bounds all attempts together, and Shutdown cuts a wait short once its
+// context is done.
+//
+// Usage:
+//
+//	composite_logger.Init(composite_logger.RetrySetting{
+//		Setting:     setting.TelegramSetting{Enabled: true, BotKey: "KEY", ChatId: 1},
+//		MaxAttempts: 5,
+//	})
+type RetrySetting struct {
+	// Setting is the wrapped adapter configuration.
+	Setting LoggerSetting
+	// MaxAttempts is the number of delivery attempts per entry, including the first one (default: 3).
+	MaxAttempts int
+	// Backoff is the wait before the first retry; it doubles with every further retry (default: 100ms).
+	// The actual wait is randomized between half and the full value.
+	Backoff time.Duration
+	// MaxBackoff caps the wait between retries (default: 5 seconds).
+	MaxBackoff time.Duration
+	// BreakerThreshold is the number of consecutive failed deliveries that opens the circuit (default: 5).
+	// A negative value disables the circuit breaker.
+	BreakerThreshold int
+	// BreakerCooldown is how long the circuit stays open before it is probed (default: 30 seconds).
+	BreakerCooldown time.Duration
+	// Retryable reports whether a failed attempt is worth retrying (default: IsTransient).
+	Retryable func(err error) bool
+}
+
+// TransientError is implemented by adapter errors that know whether a retry can succeed, such as
+// the errors of the Telegram adapter, which are transient for 429, 5xx and network failures.
+type TransientError interface {
+	error
+	// Transient reports whether the same delivery may succeed when attempted again.
+	Transient() bool
+}
+
+// RetryAfterError is implemented by adapter errors asking for a minimum wait before the next attempt,
+// such as Telegram's retry_after. RetrySetting waits at least that long, even beyond MaxBackoff.
+type RetryAfterError interface {
+	error
+	// RetryAfter returns the wait, or zero if the destination did not ask for one.
+	RetryAfter() time.Duration
+}
+
+// RetryAware is implemented by adapters that behave differently when RetrySetting retries their failures,
+// such as the Telegram adapter, which then does not send its plain text fallback.
+type RetryAware interface {
+	// WithRetries returns a copy of the adapter for use under RetrySetting.
+	WithRetries() ports.Logger
+}
+
+// IsTransient reports whether a failed delivery may succeed when retried, the default of RetrySetting.Retryable.
+// Errors implementing TransientError decide for themselves, panics are not transient, and any other error is,
+// since the adapter gives no hint.
+func IsTransient(err error) bool {
+	var transient TransientError
+	if errors.As(err, &transient) {
+		return transient.Transient()
+	}
+
+	return !errors.Is(err, ErrAdapterPanic)
+}
+
+// IsEnabled reports whether the wrapped adapter is set and enabled.
+func (r RetrySetting) IsEnabled() bool {
+	return r.Setting != nil && r.Setting.IsEnabled()
+}
+
+// InitLogger initializes the wrapped adapter and returns it wrapped with retries and the circuit breaker.
+func (r RetrySetting) InitLogger() Logger {
+	adapter := r.Setting.InitLogger()
+	if aware, ok := adapter.(RetryAware); ok {
+		adapter = aware.WithRetries()
+	}
+	l := &retryLogger{
+		adapter:    adapter,
+		logger:     asEntryLogger(adapter),
+		attempts:   r.MaxAttempts,
+		backoff:    r.Backoff,
+		maxBackoff: r.MaxBackoff,
+		threshold:  r.BreakerThreshold,
+		cooldown:   r.BreakerCooldown,
+		retryable:  r.Retryable,
+		breaker:    &breaker{},
+	}
+	if l.attempts <= 0 {
+		l.attempts = defaultRetryAttempts
+	}
+	if l.backoff <= 0 {
+		l.backoff = defaultRetryBackoff
+	}
+	if l.maxBackoff <= 0 {
+		l.maxBackoff = defaultRetryMaxBackoff
+	}
+	if l.threshold == 0 {
+		l.threshold = defaultBreakerThreshold
+	}
+	if l.cooldown <= 0 {
+		l.cooldown = defaultBreakerCooldown
+	}
+	if l.retryable == nil {
+		l.retryable = IsTransient
+	}
+
+	return l
+}
+
+// breaker holds the circuit breaker state shared by the copies of a retryLogger.
+type breaker struct {
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// retryLogger implements RetrySetting.
+type retryLogger struct {
+	adapter    ports.Logger
+	logger     EntryLogger
+	attempts   int
+	backoff    time.Duration
+	maxBackoff time.Duration
+	threshold  int
+	cooldown   time.Duration
+	retryable  func(err error) bool
+	breaker    *breaker
+	// aborted, once closed, cuts the wait before a retry short; it is the core's Shutdown signal.
+	aborted <-chan struct{}
+}
+
+func (r *retryLogger) Info(message string, context map[string]interface{}) {
+	r.Log(Entry{Level: InfoLevel, Time: time.Now(), Message: message, Fields: context})
+}
+
+func (r *retryLogger) Warn(message string, context map[string]interface{}) {
+	r.Log(Entry{Level: WarningLevel, Time: time.Now(), Message: message, Fields: context})
+}
+
+func (r *retryLogger) Error(message string, context map[string]interface{}) {
+	r.Log(Entry{Level: ErrorLevel, Time: time.Now(), Message: message, Fields: context})
+}
+
+func (r *retryLogger) Fatal(message string, context map[string]interface{}) {
+	r.Log(Entry{Level: FatalLevel, Time: time.Now(), Message: message, Fields: context})
+}
+
+// Log delivers the entry, retrying failed attempts.
+func (r *retryLogger) Log(entry Entry) {
+	_ = r.TryLog(entry)
+}
+
+// TryLog delivers the entry, retrying attempts failing with a transient error, and returns the error
+// of the last attempt, ErrCircuitOpen if the circuit is open, or a non-transient error as is.
+func (r *retryLogger) TryLog(entry Entry) error {
+	attempts, ok := r.admit(time.Now())
+	if !ok {
+		return ErrCircuitOpen
+	}
+
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if attempt > 1 && !r.wait(r.delayFor(attempt-1, err)) {
+			break
+		}
+
+		err = r.call(entry)
+		if err == nil || !r.retryable(err) {
+			r.record(nil, time.Now())
+			return err
+		}
+	}
+
+	r.record(err, time.Now())
+	return fmt.Errorf("%w: %w", ErrRetriesExhausted, err)
+}
+
+// delayFor returns the wait before the given retry: the backoff, or the wait err asks for if that is longer.
+func (r *retryLogger) delayFor(retry int, err error) time.Duration {
+	delay := r.backoffFor(retry)
+	var after RetryAfterError
+	if errors.As(err, &after) {
+		delay = max(delay, after.RetryAfter())
+	}
+
+	return delay
+}
+
+// wait sleeps for d and reports false if Shutdown gave up on the adapter meanwhile.
+func (r *retryLogger) wait(d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-r.aborted:
+		return false
+	}
+}
+
+// abortOn makes waits before retries end once aborted is closed.
+func (r *retryLogger) abortOn(aborted <-chan struct{}) {
+	r.aborted = aborted
+}
+
+// admit returns the number of attempts allowed for the next entry, or false if the circuit rejects it.
+// Only one entry at a time probes a half-open circuit, with a single attempt.
+func (r *retryLogger) admit(now time.Time) (int, bool) {
+	if r.threshold < 0 {
+		return r.attempts, true
+	}
+
+	b := r.breaker
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures < r.threshold {
+		return r.attempts, true
+	}
+	if b.probing || now.Sub(b.openedAt) < r.cooldown {
+		return 0, false
+	}
+
+	b.probing = true
+	return 1, true
+}
+
+// record updates the circuit breaker with the outcome of a delivery; nil stands for any answer of the destination.
+func (r *retryLogger) record(err error, now time.Time) {
+	if r.threshold < 0 {
+		return
+	}
+
+	b := r.breaker
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+	if err == nil {
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.failures >= r.threshold {
+		b.openedAt = now
+	}
+}
+
+// circuitState returns the current state of the circuit breaker.
+func (r *retryLogger) circuitState() CircuitState {
+	if r.threshold < 0 {
+		return CircuitNone
+	}
+
+	b := r.breaker
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch {
+	case b.failures < r.threshold:
+		return CircuitClosed
+	case b.probing || time.Since(b.openedAt) >= r.cooldown:
+		return CircuitHalfOpen
+	default:
+		return CircuitOpen
+	}
+}
+
+// backoffFor returns the randomized wait before the given retry, between half and the full exponential backoff.
+func (r *retryLogger) backoffFor(retry int) time.Duration {
+	backoff := r.backoff << (retry - 1)
+	if backoff > r.maxBackoff || backoff <= 0 {
+		backoff = r.maxBackoff
+	}
+
+	return backoff/2 + time.Duration(rand.Int64N(int64(backoff/2)+1))
+}
+
+// call invokes the adapter and turns a panic into an error.
+func (r *retryLogger) call(entry Entry) (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = fmt.Errorf("%w: %v", ErrAdapterPanic, rec)
+		}
+	}()
+
+	if checked, ok := r.logger.(CheckedEntryLogger); ok {
+		return checked.TryLog(entry)
+	}
+
+	r.logger.Log(entry)
+	return nil
+}
+
+// WithErrorHandler returns a copy of the logger whose wrapped adapter reports its own failures to handler,
+// if it does so.
+func (r *retryLogger) WithErrorHandler(handler func(entry Entry, err error)) ports.Logger {
+	reporter, ok := r.adapter.(ErrorReporter)
+	if !ok {
+		return r
+	}
+
+	clone := *r
+	clone.adapter = reporter.WithErrorHandler(handler)
+	clone.logger = asEntryLogger(clone.adapter)
+
+	return &clone
+}
+
+// Flush flushes the wrapped adapter if it buffers messages.
+func (r *retryLogger) Flush(ctx context.Context) error {
+	if flusher, ok := r.adapter.(ports.Flusher); ok {
+		return flusher.Flush(ctx)
+	}
+
+	return nil
+}
+
+// Close closes the wrapped adapter if it holds resources.
+func (r *retryLogger) Close(ctx context.Context) error {
+	if closer, ok := r.adapter.(ports.Closer); ok {
+		return closer.Close(ctx)
+	}
+
+	return nil
+}
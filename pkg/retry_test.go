@@ -0,0 +1,199 @@
+package composite_logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyLogger fails the given number of calls before it starts to succeed.
+type flakyLogger struct {
+	fakeLogger
+	failures int
+	calls    int
+}
+
+func (f *flakyLogger) TryInfo(message string, context map[string]interface{}) error {
+	f.calls++
+	if f.failures > 0 {
+		f.failures--
+		return errors.New("connection reset")
+	}
+
+	f.Info(message, context)
+	return nil
+}
+
+func (f *flakyLogger) TryWarn(string, map[string]interface{}) error  { return nil }
+func (f *flakyLogger) TryError(string, map[string]interface{}) error { return nil }
+func (f *flakyLogger) TryFatal(string, map[string]interface{}) error { return nil }
+
+// adapterError is an adapter error classified for RetrySetting.
+type adapterError struct {
+	transient  bool
+	retryAfter time.Duration
+}
+
+func (e adapterError) Error() string             { return "send failed" }
+func (e adapterError) Transient() bool           { return e.transient }
+func (e adapterError) RetryAfter() time.Duration { return e.retryAfter }
+
+// failingLogger fails every delivery with err.
+type failingLogger struct {
+	fakeLogger
+	err   error
+	calls atomic.Int64
+}
+
+func (f *failingLogger) Log(entry Entry) { _ = f.TryLog(entry) }
+
+func (f *failingLogger) TryLog(Entry) error {
+	f.calls.Add(1)
+	return f.err
+}
+
+func TestRetry_RetriesTransientFailures(t *testing.T) {
+	l := &flakyLogger{failures: 2}
+	cl, err := New(RetrySetting{Setting: testSetting{l}, Backoff: time.Millisecond})
+	require.NoError(t, err)
+	defer cl.Stop()
+
+	assert.NoError(t, cl.LogSync(InfoLevel, "sent", nil)["test"], "adapters are named after the wrapped setting")
+	assert.Equal(t, 3, l.calls)
+	assert.Len(t, l.infoCalls, 1)
+}
+
+func TestRetry_ReturnsPermanentFailuresAtOnce(t *testing.T) {
+	l := &failingLogger{err: adapterError{transient: false}}
+	cl, err := NewWithOptions(Options{ErrorHandler: func(*AdapterError) {}},
+		RetrySetting{Setting: testSetting{l}, Backoff: time.Millisecond, BreakerThreshold: 1})
+	require.NoError(t, err)
+	defer cl.Stop()
+
+	err = cl.LogSync(InfoLevel, "rejected", nil)["test"]
+
+	assert.Equal(t, adapterError{}, err)
+	assert.NotErrorIs(t, err, ErrRetriesExhausted)
+	assert.Equal(t, int64(1), l.calls.Load())
+	assert.Equal(t, CircuitClosed, cl.Health()[0].Circuit, "the destination answered")
+}
+
+func TestRetry_RetryableOverridesClassification(t *testing.T) {
+	l := &failingLogger{err: errors.New("bad request")}
+	cl, err := NewWithOptions(Options{ErrorHandler: func(*AdapterError) {}}, RetrySetting{
+		Setting:   testSetting{l},
+		Backoff:   time.Millisecond,
+		Retryable: func(err error) bool { return err.Error() != "bad request" },
+	})
+	require.NoError(t, err)
+	defer cl.Stop()
+
+	assert.EqualError(t, cl.LogSync(InfoLevel, "rejected", nil)["test"], "bad request")
+	assert.Equal(t, int64(1), l.calls.Load())
+
+	assert.True(t, IsTransient(errors.New("connection reset")))
+	assert.False(t, IsTransient(fmt.Errorf("%w: boom", ErrAdapterPanic)))
+	assert.False(t, IsTransient(fmt.Errorf("wrapped: %w", adapterError{transient: false})))
+}
+
+func TestRetry_WaitsForRetryAfter(t *testing.T) {
+	l := &failingLogger{err: adapterError{transient: true, retryAfter: 50 * time.Millisecond}}
+	cl, err := NewWithOptions(Options{ErrorHandler: func(*AdapterError) {}}, RetrySetting{
+		Setting:     testSetting{l},
+		MaxAttempts: 2,
+		Backoff:     time.Millisecond,
+		MaxBackoff:  time.Millisecond,
+	})
+	require.NoError(t, err)
+	defer cl.Stop()
+
+	start := time.Now()
+	assert.ErrorIs(t, cl.LogSync(InfoLevel, "throttled", nil)["test"], ErrRetriesExhausted)
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond, "retry_after outweighs MaxBackoff")
+	assert.Equal(t, int64(2), l.calls.Load())
+}
+
+func TestRetry_ShutdownCutsTheWaitShort(t *testing.T) {
+	l := &failingLogger{err: adapterError{transient: true, retryAfter: time.Hour}}
+	cl, err := NewWithOptions(Options{ErrorHandler: func(*AdapterError) {}},
+		FailoverSetting{Settings: []LoggerSetting{RetrySetting{Setting: testSetting{l}}, testSetting{&fakeLogger{}}}})
+	require.NoError(t, err)
+
+	cl.Info("throttled", nil)
+	require.Eventually(t, func() bool { return l.calls.Load() == 1 }, time.Second, time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, _ = cl.Shutdown(ctx)
+
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Eventually(t, func() bool { return cl.Health()[0].Queued == 0 && !cl.core.sinks[0].inFlight.Load() },
+		time.Second, time.Millisecond, "the retry gave up instead of waiting for an hour")
+}
+
+func TestRetry_CircuitOpensAndProbesHalfOpen(t *testing.T) {
+	l := &flakyLogger{failures: 4}
+	cl, err := NewWithOptions(Options{ErrorHandler: func(*AdapterError) {}}, RetrySetting{
+		Setting:          testSetting{l},
+		MaxAttempts:      2,
+		Backoff:          time.Millisecond,
+		BreakerThreshold: 2,
+		BreakerCooldown:  20 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	defer cl.Stop()
+
+	assert.Equal(t, CircuitClosed, cl.Health()[0].Circuit)
+	for i := 0; i < 2; i++ {
+		assert.ErrorIs(t, cl.LogSync(InfoLevel, "lost", nil)["test"], ErrRetriesExhausted)
+	}
+	assert.Equal(t, CircuitOpen, cl.Health()[0].Circuit)
+
+	assert.ErrorIs(t, cl.LogSync(InfoLevel, "rejected", nil)["test"], ErrCircuitOpen)
+	assert.Equal(t, 4, l.calls, "an open circuit does not call the adapter")
+
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, CircuitHalfOpen, cl.Health()[0].Circuit)
+	assert.NoError(t, cl.LogSync(InfoLevel, "probe", nil)["test"])
+	assert.Equal(t, CircuitClosed, cl.Health()[0].Circuit)
+	assert.Equal(t, 5, l.calls)
+}
+
+func TestRetry_FailedProbeReopensCircuit(t *testing.T) {
+	l := &flakyLogger{failures: 10}
+	cl, err := NewWithOptions(Options{ErrorHandler: func(*AdapterError) {}}, RetrySetting{
+		Setting:          testSetting{l},
+		MaxAttempts:      1,
+		BreakerThreshold: 1,
+		BreakerCooldown:  20 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	defer cl.Stop()
+
+	cl.LogSync(InfoLevel, "lost", nil)
+	time.Sleep(30 * time.Millisecond)
+	assert.ErrorIs(t, cl.LogSync(InfoLevel, "probe", nil)["test"], ErrRetriesExhausted)
+
+	assert.Equal(t, CircuitOpen, cl.Health()[0].Circuit)
+	assert.Equal(t, 2, l.calls)
+}
+
+func TestRetry_BackoffGrowsWithJitterUpToCap(t *testing.T) {
+	r := RetrySetting{Setting: testSetting{&fakeLogger{}}, Backoff: 100 * time.Millisecond, MaxBackoff: time.Second}.InitLogger().(*retryLogger)
+
+	for retry, full := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 4: 800 * time.Millisecond, 10: time.Second} {
+		backoff := r.backoffFor(retry)
+		assert.GreaterOrEqual(t, backoff, full/2)
+		assert.LessOrEqual(t, backoff, full)
+	}
+
+	assert.Equal(t, CircuitNone, (&sink{adapter: &fakeLogger{}}).circuitState())
+	assert.Equal(t, "half-open", CircuitHalfOpen.String())
+}
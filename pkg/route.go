@@ -0,0 +1,158 @@
+package composite_logger
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+)
+
+// Route sends the entries it matches to the named adapters only. An entry matches if it satisfies every
+// condition that is set; a route without conditions matches every entry.
+// Routes are checked in order and the first match decides; entries matching no route go to every adapter
+// except those with AdapterSetting.RoutedOnly. Fatal entries are routed like any other entry.
+//
+// Usage:
+//
+//	composite_logger.Options{Routes: []composite_logger.Route{
+//		{MinLevel: composite_logger.ErrorLevel, Fields: map[string]interface{}{"component": "payments"}, Adapters: []string{"payments"}},
+//		{Fields: map[string]interface{}{"audit": true}, Adapters: []string{"audit"}},
+//	}}
+type Route struct {
+	// MinLevel is the lowest level the route matches. Zero matches every level.
+	MinLevel Level
+	// Fields must all be present in the entry with equal values, e.g. {"component": "payments"}.
+	// The logger name of a Named logger can be matched under the "logger" key.
+	Fields map[string]interface{}
+	// Message must match the entry message. Nil matches every message.
+	Message *regexp.Regexp
+	// Match is a custom condition. Typed fields are merged into Entry.Fields of the entry it receives.
+	// A panic counts as no match and is reported to Options.ErrorHandler.
+	Match func(entry Entry) bool
+	// Adapters are the names of the adapters that receive matching entries, see AdapterSetting.Name.
+	// Names of disabled adapters are allowed; matching entries are simply not delivered to them.
+	Adapters []string
+}
+
+// compiledRoute is a Route with its adapter names resolved to sinks.
+type compiledRoute struct {
+	Route
+	targets []*sink
+	skipped []*sink
+}
+
+// router picks the adapters of an entry according to Options.Routes.
+type router struct {
+	routes   []compiledRoute
+	fallback compiledRoute
+	onError  ErrorHandler
+	// custom is set when a route has a Match condition, which reads typed fields from Entry.Fields.
+	custom bool
+}
+
+// newRouter resolves the adapter names of the routes. known holds the names of all configured adapters,
+// including disabled ones. It returns nil if there is nothing to route.
+func newRouter(routes []Route, sinks []*sink, known map[string]bool, onError ErrorHandler) (*router, error) {
+	routedOnly := false
+	for _, s := range sinks {
+		routedOnly = routedOnly || s.routedOnly
+	}
+	if len(routes) == 0 && !routedOnly {
+		return nil, nil
+	}
+
+	r := &router{onError: onError}
+	for i, route := range routes {
+		names := make(map[string]bool, len(route.Adapters))
+		for _, name := range route.Adapters {
+			if !known[name] {
+				return nil, fmt.Errorf("composite_logger: route %d names unknown adapter %q", i, name)
+			}
+			names[name] = true
+		}
+
+		r.routes = append(r.routes, compile(route, sinks, func(s *sink) bool { return names[s.name] }))
+		r.custom = r.custom || route.Match != nil
+	}
+	r.fallback = compile(Route{}, sinks, func(s *sink) bool { return !s.routedOnly })
+
+	return r, nil
+}
+
+// compile splits the sinks into those that receive the entries of the route and those that skip them.
+func compile(route Route, sinks []*sink, receives func(s *sink) bool) compiledRoute {
+	compiled := compiledRoute{Route: route}
+	for _, s := range sinks {
+		if receives(s) {
+			compiled.targets = append(compiled.targets, s)
+		} else {
+			compiled.skipped = append(compiled.skipped, s)
+		}
+	}
+
+	return compiled
+}
+
+// route returns the route of the entry. Entries produced by the logger itself are not routed;
+// callers send them to every adapter.
+func (r *router) route(entry Entry) *compiledRoute {
+	if r.custom {
+		entry = entry.flatten()
+	}
+	for i := range r.routes {
+		if r.matches(&r.routes[i].Route, entry) {
+			return &r.routes[i]
+		}
+	}
+
+	return &r.fallback
+}
+
+func (r *router) matches(route *Route, entry Entry) bool {
+	if route.MinLevel != 0 && entry.Level < route.MinLevel {
+		return false
+	}
+	if route.Message != nil && !route.Message.MatchString(entry.Message) {
+		return false
+	}
+	for key, expected := range route.Fields {
+		if value, ok := entry.field(key); !ok || !reflect.DeepEqual(value, expected) {
+			return false
+		}
+	}
+	if route.Match == nil {
+		return true
+	}
+
+	matched, err := runMatch(route.Match, entry)
+	if err != nil {
+		reportError(r.onError, &AdapterError{Entry: entry, Err: err})
+	}
+
+	return matched
+}
+
+// runMatch calls a custom route condition and turns a panic into an error.
+func runMatch(match func(Entry) bool, entry Entry) (matched bool, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", ErrRoutePanic, r)
+		}
+	}()
+
+	return match(entry), nil
+}
+
+// field returns the value of a field of the entry, whether it was logged as a typed field or in the context map.
+func (e Entry) field(key string) (interface{}, bool) {
+	for i := len(e.Attrs) - 1; i >= 0; i-- {
+		if e.Attrs[i].Key == key {
+			return e.Attrs[i].Value(), true
+		}
+	}
+	if key == LoggerKey && e.Logger != "" {
+		return e.Logger, true
+	}
+
+	value, ok := e.Fields[key]
+	return value, ok
+}
@@ -0,0 +1,109 @@
+package composite_logger
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoutes_FirstMatchDecides(t *testing.T) {
+	console := &fakeLogger{}
+	payments := &fakeLogger{}
+	audit := &fakeLogger{}
+
+	cl, err := NewWithOptions(Options{Routes: []Route{
+		{MinLevel: ErrorLevel, Fields: map[string]interface{}{"component": "payments"}, Adapters: []string{"payments", "console"}},
+		{Message: regexp.MustCompile(`^audit:`), Adapters: []string{"audit"}},
+	}},
+		AdapterSetting{Setting: testSetting{console}, Name: "console"},
+		AdapterSetting{Setting: testSetting{payments}, Name: "payments", RoutedOnly: true},
+		AdapterSetting{Setting: testSetting{audit}, Name: "audit", RoutedOnly: true},
+	)
+	require.NoError(t, err)
+
+	cl.Error("charge failed", map[string]interface{}{"component": "payments"})
+	cl.Warn("charge slow", map[string]interface{}{"component": "payments"})
+	cl.Info("audit: user deleted", nil)
+	cl.With(map[string]interface{}{"component": "payments"}).ErrorFields("refund failed", F.Err(errors.New("declined")))
+	cl.Stop()
+
+	require.Len(t, payments.errorCalls, 2)
+	assert.Equal(t, "charge failed", payments.errorCalls[0].message)
+	assert.Equal(t, "refund failed", payments.errorCalls[1].message)
+	assert.Empty(t, payments.warnCalls)
+
+	require.Len(t, audit.infoCalls, 1)
+	assert.Equal(t, "audit: user deleted", audit.infoCalls[0].message)
+
+	assert.Len(t, console.errorCalls, 2)
+	assert.Len(t, console.warnCalls, 1, "unmatched entries go to adapters without RoutedOnly")
+	assert.Empty(t, console.infoCalls)
+}
+
+func TestRoutes_LogSyncReportsUnroutedAdapters(t *testing.T) {
+	cl, err := NewWithOptions(Options{Routes: []Route{
+		{Match: func(entry Entry) bool { return entry.Fields["audit"] == true }, Adapters: []string{"audit"}},
+	}}, AdapterSetting{Setting: testSetting{&fakeLogger{}}, Name: "console"},
+		AdapterSetting{Setting: testSetting{&fakeLogger{}}, Name: "audit"})
+	require.NoError(t, err)
+	defer cl.Stop()
+
+	results := cl.LogSync(InfoLevel, "user deleted", map[string]interface{}{"audit": true})
+
+	assert.NoError(t, results["audit"])
+	assert.ErrorIs(t, results["console"], ErrEntryNotRouted)
+}
+
+func TestRoutes_MatchSeesTypedFields(t *testing.T) {
+	console := &fakeLogger{}
+	audit := &attrRecorder{}
+
+	cl, err := NewWithOptions(Options{Routes: []Route{
+		{Match: func(entry Entry) bool { return entry.Fields["audit"] == true }, Adapters: []string{"audit"}},
+	}}, AdapterSetting{Setting: testSetting{console}, Name: "console"},
+		AdapterSetting{Setting: testSetting{audit}, Name: "audit", RoutedOnly: true})
+	require.NoError(t, err)
+
+	cl.InfoFields("user deleted", F.Bool("audit", true), F.String("user", "alice"))
+	cl.InfoFields("user seen", F.String("user", "bob"))
+	cl.Stop()
+
+	require.Len(t, audit.typed, 1)
+	assert.Equal(t, "user deleted", audit.typed[0].Message)
+	assert.Equal(t, []Field{F.Bool("audit", true), F.String("user", "alice")}, audit.typed[0].Attrs,
+		"adapters still receive the typed fields")
+
+	require.Len(t, console.infoCalls, 1)
+	assert.Equal(t, "user seen", console.infoCalls[0].message)
+}
+
+func TestRoutes_PanickingMatchIsReported(t *testing.T) {
+	l := &fakeLogger{}
+	recorder := &errorRecorder{}
+
+	cl, err := NewWithOptions(Options{
+		ErrorHandler: recorder.handle,
+		Routes:       []Route{{Match: func(Entry) bool { panic("boom") }, Adapters: []string{"nowhere"}}},
+	}, testSetting{l}, AdapterSetting{Setting: testSetting{&fakeLogger{}}, Name: "nowhere", RoutedOnly: true})
+	require.NoError(t, err)
+
+	cl.Info("still delivered", nil)
+	cl.Stop()
+
+	assert.Len(t, l.infoCalls, 1)
+	require.Len(t, recorder.errors, 1)
+	assert.ErrorIs(t, recorder.errors[0].Err, ErrRoutePanic)
+}
+
+func TestRoutes_UnknownAdapterName(t *testing.T) {
+	_, err := NewWithOptions(Options{Routes: []Route{{Adapters: []string{"missing"}}}}, testSetting{&fakeLogger{}})
+	assert.EqualError(t, err, `composite_logger: route 0 names unknown adapter "missing"`)
+
+	cl, err := NewWithOptions(Options{Routes: []Route{{Adapters: []string{"disabled"}}}},
+		testSetting{&fakeLogger{}}, AdapterSetting{Name: "disabled"})
+	require.NoError(t, err, "routes may name disabled adapters")
+	cl.Stop()
+}
@@ -0,0 +1,219 @@
+package composite_logger
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultSamplingInterval is the sampling window when none is configured.
+	defaultSamplingInterval = time.Second
+	// maxReportedKeys bounds the per-key counts included in a suppression summary.
+	maxReportedKeys = 20
+)
+
+// Sampling limits repeated entries: in every Interval the First entries with the same key are logged,
+// then only every Thereafter-th. Fatal entries are never sampled.
+//
+// Usage:
+//
+//	composite_logger.InitWithOptions(composite_logger.Options{
+//		Sampling: &composite_logger.Sampling{First: 10, Thereafter: 100, Interval: time.Second},
+//	}, setting.ConsoleSetting{Enabled: true})
+type Sampling struct {
+	// First is the number of entries per key logged in every interval.
+	First int
+	// Thereafter logs every Thereafter-th entry after the first ones. Zero suppresses all of them.
+	Thereafter int
+	// Interval is the length of the sampling window (default: 1 second).
+	Interval time.Duration
+	// Key groups entries for sampling (default: level and message).
+	// Typed fields are merged into Entry.Fields of the entry it receives, like adapters without AttrLogger see them.
+	Key func(entry Entry) string
+}
+
+// RateLimit is a token bucket limiting how many entries per second an adapter receives.
+// Fatal entries are never rate limited.
+//
+// Usage:
+//
+//	composite_logger.AdapterSetting{
+//		Setting:   setting.TelegramSetting{Enabled: true, BotKey: "KEY", ChatId: 1},
+//		RateLimit: &composite_logger.RateLimit{PerSecond: 1, Burst: 20},
+//	}
+type RateLimit struct {
+	// PerSecond is the sustained number of entries per second.
+	PerSecond float64
+	// Burst is the number of entries that may be sent at once after a quiet period (default: PerSecond rounded up, at least 1).
+	Burst int
+}
+
+func samplingKey(entry Entry) string {
+	return entry.Level.String() + ":" + entry.Message
+}
+
+// suppression counts entries withheld by a sampler or rate limiter since the last summary.
+type suppression struct {
+	mu     sync.Mutex
+	counts map[string]uint64
+	total  uint64
+}
+
+func (s *suppression) add(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.counts == nil {
+		s.counts = make(map[string]uint64)
+	}
+	s.counts[key]++
+	s.total++
+}
+
+// summary returns a warning about the suppressed entries and resets the counts, or false if nothing was suppressed.
+func (s *suppression) summary(reason string) (Entry, bool) {
+	s.mu.Lock()
+	counts, total := s.counts, s.total
+	s.counts, s.total = nil, 0
+	s.mu.Unlock()
+
+	if total == 0 {
+		return Entry{}, false
+	}
+
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	if len(keys) > maxReportedKeys {
+		keys = keys[:maxReportedKeys]
+	}
+
+	byKey := make(map[string]interface{}, len(keys))
+	for _, key := range keys {
+		byKey[key] = counts[key]
+	}
+
+	return Entry{
+		Level:   WarningLevel,
+		Time:    time.Now(),
+		Message: fmt.Sprintf("%d log entries suppressed by %s", total, reason),
+		Fields: map[string]interface{}{
+			"suppressed":        total,
+			"suppressed_by_key": byKey,
+		},
+		internal: true,
+	}, true
+}
+
+// sampler implements Sampling. Counts are kept per key for the current window only.
+type sampler struct {
+	rule Sampling
+	// customKey is set when Sampling.Key was configured; only then do typed fields have to be flattened.
+	customKey bool
+	suppression
+
+	mu          sync.Mutex
+	windowStart time.Time
+	counts      map[string]int
+}
+
+func newSampler(rule *Sampling) *sampler {
+	if rule == nil {
+		return nil
+	}
+
+	s := &sampler{rule: *rule, customKey: rule.Key != nil, counts: make(map[string]int)}
+	if s.rule.Interval <= 0 {
+		s.rule.Interval = defaultSamplingInterval
+	}
+	if s.rule.Key == nil {
+		s.rule.Key = samplingKey
+	}
+
+	return s
+}
+
+// allow reports whether the entry is logged, counting it as suppressed otherwise.
+func (s *sampler) allow(entry Entry, now time.Time) bool {
+	if s == nil || entry.internal || entry.Level == FatalLevel {
+		return true
+	}
+
+	if s.customKey {
+		entry = entry.flatten()
+	}
+	key := s.rule.Key(entry)
+
+	s.mu.Lock()
+	if now.Sub(s.windowStart) >= s.rule.Interval {
+		s.windowStart = now
+		s.counts = make(map[string]int)
+	}
+	s.counts[key]++
+	n := s.counts[key]
+	s.mu.Unlock()
+
+	if n <= s.rule.First || (s.rule.Thereafter > 0 && (n-s.rule.First)%s.rule.Thereafter == 0) {
+		return true
+	}
+
+	s.add(key)
+	return false
+}
+
+// rateLimiter implements RateLimit.
+type rateLimiter struct {
+	perSecond float64
+	burst     float64
+	suppression
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(limit *RateLimit) *rateLimiter {
+	if limit == nil || limit.PerSecond <= 0 {
+		return nil
+	}
+
+	burst := float64(limit.Burst)
+	if burst <= 0 {
+		burst = math.Max(1, math.Ceil(limit.PerSecond))
+	}
+
+	return &rateLimiter{perSecond: limit.PerSecond, burst: burst, tokens: burst}
+}
+
+// allow takes a token for the entry, counting it as suppressed if the bucket is empty.
+func (r *rateLimiter) allow(entry Entry, now time.Time) bool {
+	if r == nil || entry.internal || entry.Level == FatalLevel {
+		return true
+	}
+
+	r.mu.Lock()
+	if !r.last.IsZero() {
+		r.tokens = math.Min(r.burst, r.tokens+now.Sub(r.last).Seconds()*r.perSecond)
+	}
+	r.last = now
+	allowed := r.tokens >= 1
+	if allowed {
+		r.tokens--
+	}
+	r.mu.Unlock()
+
+	if !allowed {
+		r.add(samplingKey(entry))
+	}
+
+	return allowed
+}
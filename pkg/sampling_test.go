@@ -0,0 +1,128 @@
+package composite_logger
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSampler_FirstThenEveryMth(t *testing.T) {
+	s := newSampler(&Sampling{First: 2, Thereafter: 3, Interval: time.Minute})
+	now := time.Now()
+
+	var allowed []int
+	for i := 1; i <= 10; i++ {
+		if s.allow(Entry{Level: ErrorLevel, Message: "db down"}, now) {
+			allowed = append(allowed, i)
+		}
+	}
+
+	assert.Equal(t, []int{1, 2, 5, 8}, allowed)
+	assert.True(t, s.allow(Entry{Level: WarningLevel, Message: "db down"}, now), "keys include the level")
+	assert.True(t, s.allow(Entry{Level: FatalLevel, Message: "db down"}, now), "fatal entries are never sampled")
+	assert.True(t, s.allow(Entry{Level: ErrorLevel, Message: "db down"}, now.Add(time.Minute)), "a new window starts over")
+
+	summary, ok := s.summary("sampling")
+	require.True(t, ok)
+	assert.Equal(t, "6 log entries suppressed by sampling", summary.Message)
+	assert.Equal(t, map[string]interface{}{"error:db down": uint64(6)}, summary.Fields["suppressed_by_key"])
+
+	_, ok = s.summary("sampling")
+	assert.False(t, ok, "counts are reset after a summary")
+}
+
+func TestSampler_CustomKey(t *testing.T) {
+	s := newSampler(&Sampling{First: 1, Key: func(entry Entry) string {
+		return entry.Fields["tenant"].(string)
+	}})
+	now := time.Now()
+
+	assert.True(t, s.allow(Entry{Message: "a", Fields: map[string]interface{}{"tenant": "acme"}}, now))
+	assert.False(t, s.allow(Entry{Message: "b", Fields: map[string]interface{}{"tenant": "acme"}}, now))
+	assert.True(t, s.allow(Entry{Message: "a", Fields: map[string]interface{}{"tenant": "globex"}}, now))
+}
+
+func TestSampler_CustomKeySeesTypedFields(t *testing.T) {
+	s := newSampler(&Sampling{First: 1, Key: func(entry Entry) string {
+		tenant, _ := entry.Fields["tenant"].(string)
+		return tenant
+	}})
+	now := time.Now()
+	typed := func(tenant string) Entry {
+		return Entry{Message: "a", Attrs: []Field{F.String("tenant", tenant)}, typed: true}
+	}
+
+	assert.True(t, s.allow(typed("acme"), now))
+	assert.False(t, s.allow(typed("acme"), now))
+	assert.True(t, s.allow(typed("globex"), now), "typed fields are part of the key")
+}
+
+func TestRateLimiter_TokenBucket(t *testing.T) {
+	r := newRateLimiter(&RateLimit{PerSecond: 2, Burst: 3})
+	now := time.Now()
+	entry := Entry{Level: ErrorLevel, Message: "flood"}
+
+	for i := 0; i < 3; i++ {
+		assert.True(t, r.allow(entry, now))
+	}
+	assert.False(t, r.allow(entry, now))
+	assert.True(t, r.allow(entry, now.Add(500*time.Millisecond)), "one token refills every half second")
+	assert.False(t, r.allow(entry, now.Add(500*time.Millisecond)))
+
+	summary, ok := r.summary("rate limit")
+	require.True(t, ok)
+	assert.Equal(t, uint64(2), summary.Fields["suppressed"])
+}
+
+func TestSampling_GlobalAndPerAdapterWithSummaries(t *testing.T) {
+	file := &fakeLogger{}
+	telegram := &fakeLogger{}
+
+	cl, err := NewWithOptions(Options{
+		Sampling:           &Sampling{First: 5, Interval: time.Minute},
+		DropReportInterval: -1,
+	}, AdapterSetting{Setting: testSetting{file}, Name: "file"},
+		AdapterSetting{Setting: testSetting{telegram}, Name: "telegram", RateLimit: &RateLimit{PerSecond: 0.001, Burst: 2}})
+	require.NoError(t, err)
+
+	for i := 0; i < 10; i++ {
+		cl.Error("db down", nil)
+	}
+	results := cl.LogSync(ErrorLevel, "db down", nil)
+	cl.Stop()
+
+	assert.ErrorIs(t, results["file"], ErrEntrySuppressed)
+	assert.Len(t, file.errorCalls, 5)
+	assert.Len(t, telegram.errorCalls, 2)
+
+	require.Len(t, file.warnCalls, 1)
+	assert.Equal(t, "6 log entries suppressed by sampling", file.warnCalls[0].message)
+	require.Len(t, telegram.warnCalls, 2)
+	assert.Equal(t, "6 log entries suppressed by sampling", telegram.warnCalls[0].message)
+	assert.Equal(t, "3 log entries suppressed by rate limit", telegram.warnCalls[1].message)
+}
+
+func TestSampling_SummaryKeysAreRedacted(t *testing.T) {
+	l := &fakeLogger{}
+	cl, err := NewWithOptions(Options{
+		Redaction:          DefaultRedactionRules(),
+		Sampling:           &Sampling{First: 1, Interval: time.Minute},
+		DropReportInterval: -1,
+	}, testSetting{l})
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		cl.Info("charge failed for card 4111 1111 1111 1111", nil)
+	}
+	cl.Stop()
+
+	require.Len(t, l.warnCalls, 1)
+	byKey := l.warnCalls[0].context["suppressed_by_key"].(map[string]interface{})
+	require.Len(t, byKey, 1)
+	for key, count := range byKey {
+		assert.NotContains(t, key, "4111 1111 1111 1111")
+		assert.Equal(t, uint64(2), count)
+	}
+}
@@ -0,0 +1,157 @@
+package composite_logger
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ShutdownReport describes the entries that were not delivered when the Shutdown deadline expired.
+type ShutdownReport struct {
+	// Undelivered holds, per adapter name, the number of entries that were still queued or being delivered.
+	// Adapters that handled all their entries are not listed.
+	Undelivered map[string]int
+	// Pending is the number of entries still in the main log queue, not yet handed to any adapter.
+	// They are not included in Undelivered.
+	Pending int
+}
+
+// Complete reports whether every adapter handled all its entries before the deadline.
+func (r ShutdownReport) Complete() bool {
+	return len(r.Undelivered) == 0 && r.Pending == 0
+}
+
+// Flush waits until every entry logged before the call has been handled by all adapters,
+// then calls Flush on adapters implementing ports.Flusher. The logger keeps running.
+// It returns ctx.Err() wrapped in an AdapterError for every adapter that did not finish in time,
+// and the errors of failed adapter flushes.
+//
+// Usage:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+//	defer cancel()
+//	if err := logger.Flush(ctx); err != nil {
+//		fmt.Println("logs not flushed:", err)
+//	}
+func (cl *CompositeLogger) Flush(ctx context.Context) error {
+	c, _ := cl.resolve()
+	if c == nil {
+		return nil
+	}
+
+	return c.flush(ctx)
+}
+
+// Shutdown stops the logger like Stop, but gives up waiting for the adapters when ctx is done.
+// Log calls and queues still waiting for room at that point discard their entries, counting them as dropped.
+// Adapters that handled all their entries are flushed and closed (see ports.Flusher and ports.Closer);
+// the report lists the entries left undelivered by the others. The returned error joins ctx.Err()
+// for every unfinished adapter and the errors of failed flushes and closes.
+// Shutdown on a logger returned by With or Named does nothing.
+//
+// Usage:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+//	defer cancel()
+//	report, err := logger.Shutdown(ctx)
+//	if !report.Complete() {
+//		fmt.Println("undelivered log entries:", report.Undelivered, err)
+//	}
+func (cl *CompositeLogger) Shutdown(ctx context.Context) (ShutdownReport, error) {
+	if cl == nil || cl.derived || cl.core == nil {
+		return ShutdownReport{}, nil
+	}
+
+	return cl.core.shutdown(ctx)
+}
+
+// flush sends a barrier through the queues and waits until every adapter has handled it.
+func (c *core) flush(ctx context.Context) error {
+	d := newDelivery(len(c.sinks))
+
+	c.mu.RLock()
+	if c.closed {
+		c.mu.RUnlock()
+		return ErrLoggerStopped
+	}
+	c.queue.push(Entry{barrier: ctx, delivery: d})
+	c.mu.RUnlock()
+
+	select {
+	case <-d.done:
+	case <-ctx.Done():
+	}
+
+	return c.adapterErrors(c.results(d, ctx.Err()))
+}
+
+// shutdown closes the core and closes every adapter as soon as it has handled its last entry,
+// giving up on the adapters that have not finished when ctx is done.
+func (c *core) shutdown(ctx context.Context) (ShutdownReport, error) {
+	if !c.close(ctx) {
+		return ShutdownReport{}, nil
+	}
+
+	errs := make([]error, len(c.sinks))
+	var wg sync.WaitGroup
+	for i, s := range c.sinks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case <-s.stopped:
+				errs[i] = s.close(ctx)
+			case <-ctx.Done():
+				errs[i] = ctx.Err()
+			}
+		}()
+	}
+	wg.Wait()
+
+	report := ShutdownReport{Undelivered: make(map[string]int), Pending: len(c.queue.ch)}
+	results := make(map[string]error, len(c.sinks))
+	for i, s := range c.sinks {
+		results[s.name] = errs[i]
+		select {
+		case <-s.stopped:
+		default:
+			report.Undelivered[s.name] = s.pending()
+		}
+	}
+
+	return report, c.adapterErrors(results)
+}
+
+// adapterErrors joins the non-nil results, in adapter order, as AdapterErrors.
+func (c *core) adapterErrors(results map[string]error) error {
+	var errs []error
+	for _, s := range c.sinks {
+		if err := results[s.name]; err != nil {
+			errs = append(errs, &AdapterError{Adapter: s.name, Err: err})
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Flush waits until every entry logged to the global logger before the call has been handled by all adapters.
+func Flush(ctx context.Context) error {
+	return Default().Flush(ctx)
+}
+
+// Shutdown stops the global logger, giving up waiting for the adapters when ctx is done.
+//
+// Usage:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+//	defer cancel()
+//	report, err := composite_logger.Shutdown(ctx)
+func Shutdown(ctx context.Context) (ShutdownReport, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	if previous := instance.Swap(nil); previous != nil {
+		return previous.Shutdown(ctx)
+	}
+
+	return ShutdownReport{}, nil
+}
@@ -0,0 +1,217 @@
+package composite_logger
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lifecycleLogger records Flush and Close calls.
+type lifecycleLogger struct {
+	fakeLogger
+	flushed int
+	closed  int
+}
+
+func (l *lifecycleLogger) Flush(context.Context) error {
+	l.flushed++
+	return nil
+}
+
+func (l *lifecycleLogger) Close(context.Context) error {
+	l.closed++
+	return nil
+}
+
+func TestFlush_WaitsForQueuedEntriesAndKeepsRunning(t *testing.T) {
+	l := &lifecycleLogger{}
+	cl, err := New(testSetting{l})
+	require.NoError(t, err)
+	defer cl.Stop()
+
+	for i := 0; i < 50; i++ {
+		cl.Info("queued", nil)
+	}
+	require.NoError(t, cl.Flush(context.Background()))
+
+	assert.Len(t, l.infoCalls, 50)
+	assert.Equal(t, 1, l.flushed)
+	assert.Equal(t, 0, l.closed)
+
+	cl.Info("after flush", nil)
+	require.NoError(t, cl.Flush(context.Background()))
+	assert.Len(t, l.infoCalls, 51)
+}
+
+func TestFlush_GivesUpWhenContextIsDone(t *testing.T) {
+	hung := blockingLogger{release: make(chan struct{})}
+	defer close(hung.release)
+
+	cl, err := New(AdapterSetting{Setting: testSetting{hung}, Name: "telegram"}, testSetting{&fakeLogger{}})
+	require.NoError(t, err)
+
+	cl.Info("stuck", nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err = cl.Flush(ctx)
+
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	var adapterErr *AdapterError
+	require.True(t, errors.As(err, &adapterErr))
+	assert.Equal(t, "telegram", adapterErr.Adapter)
+	assert.NotContains(t, err.Error(), `"test"`)
+}
+
+func TestFlush_HungAdapterDoesNotStallOthers(t *testing.T) {
+	hung := blockingLogger{release: make(chan struct{})}
+	fast := signalLogger{received: make(chan string, 10)}
+
+	cl, err := New(
+		AdapterSetting{Setting: testSetting{hung}, QueueSize: 1, OverflowPolicy: OverflowDropNewest},
+		testSetting{fast},
+	)
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		cl.Info("queued", nil)
+		<-fast.received
+	}
+
+	flushed := make(chan error, 1)
+	go func() { flushed <- cl.Flush(context.Background()) }()
+
+	cl.Info("after flush", nil)
+	select {
+	case msg := <-fast.received:
+		assert.Equal(t, "after flush", msg)
+	case <-time.After(time.Second):
+		t.Fatal("a pending flush of a hung adapter stalled the others")
+	}
+
+	close(hung.release)
+	require.NoError(t, <-flushed)
+	cl.Stop()
+}
+
+func TestFlush_StoppedLogger(t *testing.T) {
+	cl, err := New(testSetting{&fakeLogger{}})
+	require.NoError(t, err)
+	cl.Stop()
+
+	assert.ErrorIs(t, cl.Flush(context.Background()), ErrLoggerStopped)
+}
+
+func TestShutdown_ClosesAdapters(t *testing.T) {
+	l := &lifecycleLogger{}
+	cl, err := New(testSetting{l})
+	require.NoError(t, err)
+
+	cl.Info("last", nil)
+	report, err := cl.Shutdown(context.Background())
+
+	require.NoError(t, err)
+	assert.True(t, report.Complete())
+	assert.Len(t, l.infoCalls, 1)
+	assert.Equal(t, 1, l.flushed)
+	assert.Equal(t, 1, l.closed)
+
+	report, err = cl.Shutdown(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, report.Complete())
+	assert.Equal(t, 1, l.closed, "adapters are closed only once")
+}
+
+func TestShutdown_BoundedWhenQueuesAreWedged(t *testing.T) {
+	hung := blockingLogger{release: make(chan struct{})}
+	defer close(hung.release)
+
+	cl, err := NewWithOptions(Options{QueueSize: 1, DropReportInterval: -1},
+		AdapterSetting{Setting: testSetting{hung}, QueueSize: 1, OverflowPolicy: OverflowBlock})
+	require.NoError(t, err)
+
+	logged := make(chan struct{})
+	go func() {
+		defer close(logged)
+		for i := 0; i < 5; i++ {
+			cl.Info("burst", nil)
+		}
+	}()
+	require.Eventually(t, func() bool { return len(cl.core.queue.ch) == 1 }, time.Second, time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		_, err = cl.Shutdown(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	case <-time.After(time.Second):
+		t.Fatal("Shutdown was not bounded by its context")
+	}
+	select {
+	case <-logged:
+	case <-time.After(time.Second):
+		t.Fatal("a log call blocked on the full queue was not released")
+	}
+	assert.NotZero(t, cl.Dropped())
+}
+
+func TestShutdown_ReportsMainQueueBacklogOnce(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	stall := func(entry Entry) (Entry, bool) {
+		<-release
+		return entry, true
+	}
+
+	cl, err := NewWithOptions(Options{Processors: []Processor{stall}, DropReportInterval: -1},
+		AdapterSetting{Setting: testSetting{&fakeLogger{}}, Name: "console"},
+		AdapterSetting{Setting: testSetting{&fakeLogger{}}, Name: "telegram"})
+	require.NoError(t, err)
+
+	for i := 0; i < 4; i++ {
+		cl.Info("message", nil)
+	}
+	require.Eventually(t, func() bool { return len(cl.core.queue.ch) == 3 }, time.Second, time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	report, _ := cl.Shutdown(ctx)
+
+	assert.False(t, report.Complete())
+	assert.Equal(t, 3, report.Pending)
+	assert.Equal(t, map[string]int{"console": 0, "telegram": 0}, report.Undelivered)
+}
+
+func TestShutdown_ReportsUndeliveredEntries(t *testing.T) {
+	hung := blockingLogger{release: make(chan struct{})}
+	defer close(hung.release)
+	healthy := &lifecycleLogger{}
+
+	cl, err := New(AdapterSetting{Setting: testSetting{hung}, Name: "telegram"}, testSetting{healthy})
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		cl.Info("message", nil)
+	}
+	require.Eventually(t, func() bool { return cl.Health()[0].Queued == 2 }, time.Second, time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	report, err := cl.Shutdown(ctx)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.False(t, report.Complete())
+	assert.Equal(t, map[string]int{"telegram": 3}, report.Undelivered)
+	assert.Equal(t, 1, healthy.closed)
+}
@@ -0,0 +1,611 @@
+package composite_logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Consolushka/golang.composite_logger/pkg/ports"
+)
+
+// sink owns the queue and worker goroutine of a single adapter,
+// so a slow destination only delays its own entries.
+// Panics and timeouts of the adapter are isolated here and reported to the error handler.
+type sink struct {
+	name       string
+	adapter    ports.Logger
+	logger     EntryLogger
+	queue      *queue
+	timeout    time.Duration
+	decorator  MessageDecorator
+	processors []Processor
+	sampler    *sampler
+	limiter    *rateLimiter
+	dedup      *deduper
+	routedOnly bool
+	// spool is set for adapters with AdapterSetting.Spool; backlog holds the entries it replays on start.
+	spool   *spool
+	backlog []Entry
+	// intake is set together with spool. The dispatcher queues entries there, and runSpool persists them
+	// before passing them on to queue, so disk writes never hold up the dispatcher.
+	intake   *queue
+	spooling atomic.Bool
+	// sequence numbers the summaries the sink produces itself; it is shared with the core.
+	sequence *atomic.Uint64
+
+	onError         ErrorHandler
+	quarantineAfter int
+
+	failures          atomic.Uint64
+	panics            atomic.Uint64
+	consecutivePanics atomic.Int64
+	quarantined       atomic.Bool
+
+	inFlight atomic.Bool
+	// running is closed when a timed out adapter call returns; it is nil while no such call is outstanding.
+	running chan struct{}
+	stopped chan struct{}
+
+	// barriers holds the flush requests waiting for the entries queued before them, see addBarrier.
+	// spoolBarriers holds those of adapters with a spool until runSpool has passed the entries before them on.
+	barriers      barrierList
+	spoolBarriers barrierList
+}
+
+func newSink(name string, logger ports.Logger, opts AdapterSetting, coreOpts Options) *sink {
+	s := &sink{
+		name: name,
+		queue: newQueue(opts.QueueSize, opts.overflowPolicy(), opts.BlockTimeout, func(d *delivery) {
+			d.complete(name, ErrEntryDropped)
+		}),
+		timeout:         opts.Timeout,
+		decorator:       opts.MessageDecorator,
+		processors:      opts.Processors,
+		sampler:         newSampler(opts.Sampling),
+		limiter:         newRateLimiter(opts.RateLimit),
+		dedup:           newDeduper(opts.Dedup),
+		routedOnly:      opts.RoutedOnly,
+		sequence:        new(atomic.Uint64),
+		onError:         coreOpts.errorHandler(),
+		quarantineAfter: coreOpts.QuarantineAfter,
+		stopped:         make(chan struct{}),
+		barriers:        barrierList{signal: make(chan struct{}, 1)},
+		spoolBarriers:   barrierList{signal: make(chan struct{}, 1)},
+	}
+	if opts.Spool != nil {
+		s.intake = newQueue(opts.QueueSize, opts.overflowPolicy(), opts.BlockTimeout, func(d *delivery) {
+			d.complete(name, ErrEntryDropped)
+		})
+	}
+
+	if reporter, ok := logger.(ErrorReporter); ok {
+		logger = reporter.WithErrorHandler(s.fail)
+	}
+	s.adapter = logger
+	s.logger = asEntryLogger(logger)
+
+	return s
+}
+
+// offer puts the entry into the sink queue according to the adapter's overflow policy.
+// With a dropping policy the dispatcher is never blocked and entries that do not fit are dropped.
+// Entries for an adapter with a spool go to its intake queue first.
+func (s *sink) offer(entry Entry) {
+	if s.intake != nil {
+		s.intake.push(entry)
+		return
+	}
+
+	s.queue.push(entry)
+}
+
+// input returns the first queue entries for the adapter go to.
+func (s *sink) input() *queue {
+	if s.intake != nil {
+		return s.intake
+	}
+
+	return s.queue
+}
+
+// openSpool opens the adapter's spool and keeps the entries left by a previous logger for delivery
+// before any new entry.
+func (s *sink) openSpool(cfg *Spool) error {
+	sp, backlog, dropped, err := openSpool(cfg)
+	if err != nil {
+		return err
+	}
+
+	s.spool = sp
+	s.backlog = backlog
+	s.queue.dropped.Add(dropped)
+
+	return nil
+}
+
+// persist appends the entry to the spool and returns its spool ID, zero if it could not be stored.
+// Entries discarded to stay within the spool limits are counted as dropped.
+func (s *sink) persist(entry Entry) uint64 {
+	id, dropped, err := s.spool.append(entry)
+	s.queue.dropped.Add(dropped)
+	if err != nil {
+		s.report(&AdapterError{Adapter: s.name, Entry: entry, Err: fmt.Errorf("%w: %w", ErrSpool, err)})
+	}
+
+	return id
+}
+
+// runSpool persists the entries of the intake queue and passes them on to the sink queue,
+// until the intake queue is closed and drained. It then closes the sink queue.
+// Entries the sink queue drops stay in the spool and are delivered by the next logger.
+func (s *sink) runSpool(wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	for {
+		select {
+		case entry, ok := <-s.intake.ch:
+			if !ok {
+				s.passBarriers(true)
+				close(s.queue.ch)
+				return
+			}
+			s.intake.taken.Add(1)
+			s.spooling.Store(true)
+			if !entry.internal {
+				entry.spoolID = s.persist(entry)
+			}
+			s.queue.push(entry)
+			s.spooling.Store(false)
+			s.passBarriers(false)
+		case <-s.spoolBarriers.signal:
+			s.passBarriers(false)
+		}
+	}
+}
+
+// passBarriers hands the flush requests whose entries have been persisted, or all of them if all is set,
+// over to the sink worker.
+func (s *sink) passBarriers(all bool) {
+	for _, b := range s.spoolBarriers.ready(s.intake.taken.Load(), all) {
+		s.barriers.add(b.entry, s.queue.pushed.Load())
+	}
+}
+
+// acknowledge removes a handled entry from the spool. Entries the adapter failed to handle stay for the next logger.
+func (s *sink) acknowledge(entry Entry, err error) {
+	if entry.spoolID == 0 {
+		return
+	}
+	if err != nil && !errors.Is(err, ErrEntrySuppressed) && !errors.Is(err, ErrEntryFiltered) {
+		return
+	}
+
+	if err := s.spool.ack(entry.spoolID); err != nil {
+		s.report(&AdapterError{Adapter: s.name, Entry: entry, Err: fmt.Errorf("%w: %w", ErrSpool, err)})
+	}
+}
+
+// run delivers queued entries until the queue is closed and drained.
+func (s *sink) run(wg *sync.WaitGroup) {
+	defer wg.Done()
+	defer close(s.stopped)
+
+	var tick <-chan time.Time
+	if s.dedup != nil {
+		ticker := time.NewTicker(s.dedup.window / 2)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for _, entry := range s.backlog {
+		s.handle(entry)
+	}
+	s.backlog = nil
+
+	for {
+		select {
+		case entry, ok := <-s.queue.ch:
+			if !ok {
+				s.drain()
+				return
+			}
+			s.queue.taken.Add(1)
+			s.handle(entry)
+			s.flushBarriers(false)
+		case <-s.barriers.signal:
+			s.flushBarriers(false)
+		case now := <-tick:
+			s.deliverAll(s.dedup.expired(now))
+		}
+	}
+}
+
+// pendingBarrier is a flush request waiting until a worker has taken mark entries from its queue.
+type pendingBarrier struct {
+	entry Entry
+	mark  uint64
+}
+
+// barrierList holds flush requests until the entries queued before them have left the queue.
+type barrierList struct {
+	mu       sync.Mutex
+	barriers []pendingBarrier
+	// pending counts the barriers, so the worker checks for them without locking after every entry.
+	pending atomic.Int32
+	// signal wakes the worker when a barrier is added.
+	signal chan struct{}
+}
+
+// add registers a flush request that is ready once mark entries have been taken from the queue, and wakes the worker.
+func (l *barrierList) add(entry Entry, mark uint64) {
+	l.mu.Lock()
+	l.barriers = append(l.barriers, pendingBarrier{entry: entry, mark: mark})
+	l.pending.Add(1)
+	l.mu.Unlock()
+
+	select {
+	case l.signal <- struct{}{}:
+	default:
+	}
+}
+
+// ready removes and returns the flush requests whose entries have been taken, or all of them if all is set.
+func (l *barrierList) ready(taken uint64, all bool) []pendingBarrier {
+	if l.pending.Load() == 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var ready []pendingBarrier
+	kept := l.barriers[:0]
+	for _, b := range l.barriers {
+		if all || b.mark <= taken {
+			ready = append(ready, b)
+		} else {
+			kept = append(kept, b)
+		}
+	}
+	l.barriers = kept
+	l.pending.Store(int32(len(kept)))
+
+	return ready
+}
+
+// addBarrier registers a flush request without waiting, so a hung adapter never blocks the dispatcher.
+// The worker completes it once every entry queued before it has been handled or dropped.
+func (s *sink) addBarrier(entry Entry) {
+	if s.intake != nil {
+		s.spoolBarriers.add(entry, s.intake.pushed.Load())
+		return
+	}
+
+	s.barriers.add(entry, s.queue.pushed.Load())
+}
+
+// flushBarriers completes the flush requests whose entries have left the queue, or all of them if all is set,
+// by delivering the held-back repeats and calling the adapter's Flush.
+func (s *sink) flushBarriers(all bool) {
+	for _, b := range s.barriers.ready(s.queue.taken.Load(), all) {
+		s.deliverAll(s.dedup.expired(time.Time{}))
+		b.entry.delivery.complete(s.name, s.flush(b.entry.barrier))
+	}
+}
+
+// handle delivers an entry, acknowledges it in the spool and records the result.
+func (s *sink) handle(entry Entry) {
+	s.inFlight.Store(true)
+	err := s.deliver(entry)
+	s.acknowledge(entry, err)
+	entry.delivery.complete(s.name, err)
+	s.inFlight.Store(false)
+}
+
+// drain delivers what the adapter still holds back before it shuts down:
+// collapsed repeats and summaries of entries suppressed after the last periodic report.
+func (s *sink) drain() {
+	s.flushBarriers(true)
+	s.deliverAll(s.dedup.expired(time.Time{}))
+
+	for _, summary := range s.summaries() {
+		summary.Sequence = s.sequence.Add(1)
+		_ = s.deliver(summary)
+	}
+
+	if s.spool != nil {
+		if err := s.spool.close(); err != nil {
+			s.report(&AdapterError{Adapter: s.name, Err: fmt.Errorf("%w: %w", ErrSpool, err)})
+		}
+	}
+}
+
+// deliverAll delivers entries produced by the sink itself.
+func (s *sink) deliverAll(entries []Entry) {
+	for _, entry := range entries {
+		_ = s.deliver(entry)
+	}
+}
+
+// deliver passes the entry to the adapter, giving up waiting once the timeout expires.
+// A timed out call keeps running in the background, but the worker moves on to the next entry.
+// Quarantined adapters are skipped.
+func (s *sink) deliver(entry Entry) error {
+	if s.quarantined.Load() {
+		return ErrAdapterQuarantined
+	}
+
+	if deliverNow, collapsed := s.dedup.admit(entry); collapsed != nil {
+		_ = s.deliver(*collapsed)
+	} else if !deliverNow {
+		return ErrEntrySuppressed
+	}
+
+	if s.sampler != nil || s.limiter != nil {
+		now := time.Now()
+		if !s.sampler.allow(entry, now) || !s.limiter.allow(entry, now) {
+			return ErrEntrySuppressed
+		}
+	}
+
+	if len(s.processors) > 0 {
+		processed, keep, err := process(s.processors, entry.flatten())
+		if err != nil {
+			s.report(&AdapterError{Adapter: s.name, Entry: entry, Err: err})
+		}
+		if !keep {
+			return ErrEntryFiltered
+		}
+		entry = processed
+	}
+
+	if s.decorator != nil {
+		entry.Message = s.decorator(entry)
+	}
+
+	if s.timeout <= 0 {
+		return s.call(entry)
+	}
+
+	return s.callWithTimeout(entry)
+}
+
+// callWithTimeout calls the adapter in a separate goroutine and stops waiting for it after the timeout.
+// It is kept apart from deliver so entries of adapters without a timeout are not moved to the heap.
+// At most one call is in flight: while a timed out call is still running, the next entry waits for it
+// within its own timeout and times out as well if it does not return. A hung adapter therefore never
+// accumulates goroutines or receives concurrent calls, and the late result of a timed out call is not counted again.
+func (s *sink) callWithTimeout(entry Entry) error {
+	timer := time.NewTimer(s.timeout)
+	defer timer.Stop()
+
+	if s.running != nil {
+		select {
+		case <-s.running:
+			s.running = nil
+		case <-timer.C:
+			s.fail(entry, ErrAdapterTimeout)
+			return ErrAdapterTimeout
+		}
+	}
+
+	finished := make(chan struct{})
+	result := make(chan error, 1)
+	var settled atomic.Bool
+	go func() {
+		defer close(finished)
+		err := s.invoke(entry)
+		if settled.CompareAndSwap(false, true) {
+			result <- s.settle(entry, err)
+		}
+	}()
+
+	select {
+	case err := <-result:
+		return err
+	case <-timer.C:
+		if !settled.CompareAndSwap(false, true) {
+			return <-result
+		}
+		s.running = finished
+		s.fail(entry, ErrAdapterTimeout)
+		return ErrAdapterTimeout
+	}
+}
+
+// call invokes the adapter and counts and reports the outcome.
+func (s *sink) call(entry Entry) error {
+	return s.settle(entry, s.invoke(entry))
+}
+
+// invoke calls the adapter and turns a panic into an adapterPanic error instead of killing the worker.
+// Typed fields are merged into the context map unless the adapter implements AttrLogger.
+func (s *sink) invoke(entry Entry) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &adapterPanic{value: r, stack: string(debug.Stack())}
+		}
+	}()
+
+	if attrLogger, ok := s.logger.(AttrLogger); ok && entry.typed {
+		return attrLogger.LogAttrs(entry)
+	}
+	if checked, ok := s.logger.(CheckedEntryLogger); ok {
+		return checked.TryLog(entry.flatten())
+	}
+
+	s.logger.Log(entry.flatten())
+	return nil
+}
+
+// settle reports the outcome of an adapter call. Panics and errors returned by adapters implementing
+// CheckedEntryLogger or AttrLogger are counted as failures.
+func (s *sink) settle(entry Entry, err error) error {
+	if p, ok := err.(*adapterPanic); ok {
+		return s.panicked(entry, p)
+	}
+
+	s.consecutivePanics.Store(0)
+	if err != nil {
+		s.fail(entry, err)
+	}
+
+	return err
+}
+
+// adapterPanic carries a panic recovered from an adapter call until it is reported.
+type adapterPanic struct {
+	value interface{}
+	stack string
+}
+
+func (p *adapterPanic) Error() string {
+	return fmt.Sprintf("%v: %v", ErrAdapterPanic, p.value)
+}
+
+// panicked reports a recovered panic and quarantines the adapter after Options.QuarantineAfter consecutive panics.
+func (s *sink) panicked(entry Entry, p *adapterPanic) error {
+	err := fmt.Errorf("%w: %v", ErrAdapterPanic, p.value)
+
+	s.panics.Add(1)
+	s.failures.Add(1)
+	s.report(&AdapterError{Adapter: s.name, Entry: entry, Err: err, Stack: p.stack})
+
+	if s.quarantineAfter > 0 && s.consecutivePanics.Add(1) >= int64(s.quarantineAfter) &&
+		s.quarantined.CompareAndSwap(false, true) {
+		s.report(&AdapterError{Adapter: s.name, Entry: entry, Err: ErrAdapterQuarantined})
+	}
+
+	return err
+}
+
+// fail counts and reports a failure to handle the entry.
+func (s *sink) fail(entry Entry, err error) {
+	s.failures.Add(1)
+	s.report(&AdapterError{Adapter: s.name, Entry: entry, Err: err})
+}
+
+// report passes the error to the handler.
+func (s *sink) report(err *AdapterError) {
+	reportError(s.onError, err)
+}
+
+// flush calls the adapter's Flush if it implements ports.Flusher.
+func (s *sink) flush(ctx context.Context) error {
+	flusher, ok := s.adapter.(ports.Flusher)
+	if !ok || s.quarantined.Load() {
+		return nil
+	}
+
+	return s.callWithContext(ctx, flusher.Flush)
+}
+
+// close flushes the adapter and releases its resources if it implements ports.Flusher or ports.Closer.
+func (s *sink) close(ctx context.Context) error {
+	if err := s.flush(ctx); err != nil {
+		return err
+	}
+
+	closer, ok := s.adapter.(ports.Closer)
+	if !ok {
+		return nil
+	}
+
+	return s.callWithContext(ctx, closer.Close)
+}
+
+// callWithContext runs an adapter hook, returning early when ctx is done and turning a panic into an error.
+func (s *sink) callWithContext(ctx context.Context, hook func(ctx context.Context) error) error {
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("%w: %v", ErrAdapterPanic, r)
+			}
+		}()
+		done <- hook(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// pending returns the number of entries queued for the adapter or being delivered to it.
+func (s *sink) pending() int {
+	n := s.queued()
+	if s.inFlight.Load() {
+		n++
+	}
+
+	return n
+}
+
+// queued returns the number of entries waiting in the adapter's queues, including one being persisted.
+func (s *sink) queued() int {
+	n := len(s.queue.ch)
+	if s.intake != nil {
+		n += len(s.intake.ch)
+		if s.spooling.Load() {
+			n++
+		}
+	}
+
+	return n
+}
+
+// dropped returns the number of entries discarded by the adapter's queues.
+func (s *sink) dropped() uint64 {
+	n := s.queue.dropped.Load()
+	if s.intake != nil {
+		n += s.intake.dropped.Load()
+	}
+
+	return n
+}
+
+// summaries returns the suppression summaries of the adapter's sampler and rate limiter.
+func (s *sink) summaries() []Entry {
+	var summaries []Entry
+	if s.sampler != nil {
+		if summary, ok := s.sampler.summary("sampling"); ok {
+			summaries = append(summaries, summary)
+		}
+	}
+	if s.limiter != nil {
+		if summary, ok := s.limiter.summary("rate limit"); ok {
+			summaries = append(summaries, summary)
+		}
+	}
+
+	return summaries
+}
+
+// status returns a snapshot of the sink's delivery state.
+func (s *sink) status() AdapterStatus {
+	return AdapterStatus{
+		Name:        s.name,
+		Queued:      s.queued(),
+		Dropped:     s.dropped(),
+		Failures:    s.failures.Load(),
+		Panics:      s.panics.Load(),
+		Quarantined: s.quarantined.Load(),
+		Circuit:     s.circuitState(),
+	}
+}
+
+// circuitState returns the state of the adapter's circuit breaker, if it has one.
+func (s *sink) circuitState() CircuitState {
+	if r, ok := s.adapter.(*retryLogger); ok {
+		return r.circuitState()
+	}
+
+	return CircuitNone
+}
@@ -0,0 +1,167 @@
+package composite_logger
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingLogger blocks every call until release is closed.
+type blockingLogger struct {
+	release chan struct{}
+}
+
+func (b blockingLogger) Info(string, map[string]interface{})  { <-b.release }
+func (b blockingLogger) Warn(string, map[string]interface{})  { <-b.release }
+func (b blockingLogger) Error(string, map[string]interface{}) { <-b.release }
+func (b blockingLogger) Fatal(string, map[string]interface{}) { <-b.release }
+
+// signalLogger reports every received message on a channel.
+type signalLogger struct {
+	received chan string
+}
+
+func (s signalLogger) Info(message string, _ map[string]interface{})  { s.received <- message }
+func (s signalLogger) Warn(message string, _ map[string]interface{})  { s.received <- message }
+func (s signalLogger) Error(message string, _ map[string]interface{}) { s.received <- message }
+func (s signalLogger) Fatal(message string, _ map[string]interface{}) { s.received <- message }
+
+func TestSink_SlowAdapterDoesNotStallOthers(t *testing.T) {
+	slow := blockingLogger{release: make(chan struct{})}
+	fast := signalLogger{received: make(chan string, 10)}
+
+	cl, err := New(testSetting{slow}, testSetting{fast})
+	require.NoError(t, err)
+
+	cl.Info("first", nil)
+	cl.Info("second", nil)
+
+	for _, expected := range []string{"first", "second"} {
+		select {
+		case msg := <-fast.received:
+			assert.Equal(t, expected, msg)
+		case <-time.After(time.Second):
+			t.Fatal("fast adapter was stalled by the slow one")
+		}
+	}
+
+	close(slow.release)
+	cl.Stop()
+}
+
+func TestSink_HungAdapterDoesNotStallCallersByDefault(t *testing.T) {
+	hung := blockingLogger{release: make(chan struct{})}
+	healthy := &fakeLogger{}
+
+	cl, err := NewWithOptions(Options{DropReportInterval: -1},
+		AdapterSetting{Setting: testSetting{hung}, Name: "telegram"},
+		AdapterSetting{Setting: testSetting{healthy}, Name: "console"})
+	require.NoError(t, err)
+	defer cl.Stop()
+	defer close(hung.release)
+
+	const total = 3 * defaultQueueSize
+	logged := make(chan struct{})
+	go func() {
+		defer close(logged)
+		for i := 0; i < total; i++ {
+			cl.Info("message", nil)
+		}
+	}()
+
+	select {
+	case <-logged:
+	case <-time.After(5 * time.Second):
+		t.Fatal("callers were stalled by the hung adapter")
+	}
+	results := cl.LogSync(InfoLevel, "last", nil)
+	assert.NoError(t, results["console"])
+	assert.ErrorIs(t, results["telegram"], ErrEntryDropped)
+	require.NotEmpty(t, healthy.infoCalls)
+	assert.Equal(t, "last", healthy.infoCalls[len(healthy.infoCalls)-1].message, "the healthy adapter keeps up")
+}
+
+func TestSink_TimeoutReleasesWorker(t *testing.T) {
+	hung := blockingLogger{release: make(chan struct{})}
+	defer close(hung.release)
+
+	s := newSink("hung", hung, AdapterSetting{Timeout: 10 * time.Millisecond}, Options{ErrorHandler: func(*AdapterError) {}})
+
+	done := make(chan struct{})
+	go func() {
+		s.deliver(Entry{Level: InfoLevel, Message: "hung"})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("deliver did not return after the adapter timeout")
+	}
+}
+
+func TestSink_TimeoutKeepsOneCallInFlight(t *testing.T) {
+	hung := &countingHungLogger{release: make(chan struct{})}
+
+	var reported atomic.Int64
+	s := newSink("hung", hung, AdapterSetting{Timeout: 5 * time.Millisecond}, Options{ErrorHandler: func(*AdapterError) {
+		reported.Add(1)
+	}})
+
+	for i := 0; i < 5; i++ {
+		assert.ErrorIs(t, s.deliver(Entry{Level: InfoLevel, Message: "hung"}), ErrAdapterTimeout)
+	}
+	assert.Equal(t, int64(1), hung.calls.Load(), "a hung adapter must not receive concurrent calls")
+
+	close(hung.release)
+	require.NoError(t, s.deliver(Entry{Level: InfoLevel, Message: "recovered"}))
+	assert.Equal(t, int64(2), hung.calls.Load())
+	assert.Equal(t, uint64(5), s.failures.Load(), "the late result of a timed out call is not counted again")
+	assert.Equal(t, int64(5), reported.Load())
+}
+
+// countingHungLogger counts its calls and blocks the first one until release is closed, then fails it.
+type countingHungLogger struct {
+	release chan struct{}
+	calls   atomic.Int64
+}
+
+func (c *countingHungLogger) TryLog(Entry) error {
+	if c.calls.Add(1) > 1 {
+		return nil
+	}
+
+	<-c.release
+	return errors.New("late failure")
+}
+
+func (c *countingHungLogger) Log(entry Entry)                      { _ = c.TryLog(entry) }
+func (c *countingHungLogger) Info(string, map[string]interface{})  {}
+func (c *countingHungLogger) Warn(string, map[string]interface{})  {}
+func (c *countingHungLogger) Error(string, map[string]interface{}) {}
+func (c *countingHungLogger) Fatal(string, map[string]interface{}) {}
+
+func TestSink_OfferDropsWhenQueueIsFull(t *testing.T) {
+	s := newSink("fake", &fakeLogger{}, AdapterSetting{QueueSize: 1, OverflowPolicy: OverflowDropNewest}, Options{})
+
+	s.offer(Entry{Level: InfoLevel, Message: "kept"})
+	s.offer(Entry{Level: InfoLevel, Message: "dropped"})
+
+	assert.Len(t, s.queue.ch, 1)
+	assert.Equal(t, uint64(1), s.queue.dropped.Load())
+}
+
+func TestAdapterSetting_DelegatesToWrappedSetting(t *testing.T) {
+	l := &fakeLogger{}
+	wrapped := AdapterSetting{Setting: testSetting{l}, QueueSize: 5}
+
+	assert.True(t, wrapped.IsEnabled())
+	assert.Same(t, l, wrapped.InitLogger())
+	assert.False(t, AdapterSetting{}.IsEnabled())
+	assert.Equal(t, 5, adapterSetting(wrapped).QueueSize)
+	assert.Equal(t, 0, adapterSetting(testSetting{l}).QueueSize)
+}
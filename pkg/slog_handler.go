@@ -0,0 +1,196 @@
+package composite_logger
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// SlogHandlerOptions configures the slog.Handler returned by NewSlogHandler.
+type SlogHandlerOptions struct {
+	// Level is the minimum level passed on to the composite logger (default: InfoLevel).
+	Level Level
+	// NestGroups keeps attribute groups as nested maps instead of flattening them into dotted keys,
+	// e.g. {"http": {"status": 200}} instead of {"http.status": 200}.
+	NestGroups bool
+}
+
+// SlogHandler is a slog.Handler that turns slog records into composite entries,
+// so slog.SetDefault can fan out to every configured adapter.
+type SlogHandler struct {
+	logger *CompositeLogger
+	opts   SlogHandlerOptions
+	groups []string
+	attrs  []groupedAttr
+}
+
+var _ slog.Handler = (*SlogHandler)(nil)
+
+// groupedAttr is an attribute bound by WithAttrs together with the groups open at that moment.
+type groupedAttr struct {
+	groups []string
+	attr   slog.Attr
+}
+
+// NewSlogHandler returns a slog.Handler that logs through cl.
+// A nil cl logs through the global logger, following its re-initialization.
+//
+// Usage:
+//
+//	slog.SetDefault(slog.New(composite_logger.NewSlogHandler(nil, nil)))
+func NewSlogHandler(cl *CompositeLogger, opts *SlogHandlerOptions) *SlogHandler {
+	if cl == nil {
+		cl = &CompositeLogger{derived: true}
+	}
+
+	h := &SlogHandler{logger: cl}
+	if opts != nil {
+		h.opts = *opts
+	}
+	if h.opts.Level == 0 {
+		h.opts.Level = InfoLevel
+	}
+
+	return h
+}
+
+// Enabled reports whether records of the given level reach the composite logger.
+func (h *SlogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return LevelFromSlog(level) >= h.opts.Level
+}
+
+// Handle converts the record into an entry and hands it over to the composite logger.
+// Fields attached to ctx with WithFields are merged in as well.
+func (h *SlogHandler) Handle(ctx context.Context, record slog.Record) error {
+	c, base := h.logger.resolve()
+	if c == nil {
+		return nil
+	}
+
+	fields := make(map[string]interface{}, len(h.attrs)+record.NumAttrs())
+	for _, ga := range h.attrs {
+		h.addAttr(fields, ga.groups, ga.attr)
+	}
+	record.Attrs(func(attr slog.Attr) bool {
+		h.addAttr(fields, h.groups, attr)
+		return true
+	})
+
+	entry := Entry{
+		Level:   LevelFromSlog(record.Level),
+		Time:    record.Time,
+		Message: record.Message,
+		Fields:  contextFields(ctx, fields),
+	}
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+	if record.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{record.PC}).Next()
+		entry.Caller = Caller{Function: frame.Function, File: frame.File, Line: frame.Line}
+	}
+
+	h.logger.emit(c, base, entry)
+
+	return nil
+}
+
+// WithAttrs returns a handler that adds the attributes, within the currently open groups, to every record.
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+
+	clone := *h
+	clone.attrs = make([]groupedAttr, len(h.attrs), len(h.attrs)+len(attrs))
+	copy(clone.attrs, h.attrs)
+	for _, attr := range attrs {
+		clone.attrs = append(clone.attrs, groupedAttr{groups: h.groups, attr: attr})
+	}
+
+	return &clone
+}
+
+// WithGroup returns a handler that qualifies all following attributes with the group name.
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	clone := *h
+	clone.groups = make([]string, len(h.groups), len(h.groups)+1)
+	copy(clone.groups, h.groups)
+	clone.groups = append(clone.groups, name)
+
+	return &clone
+}
+
+// addAttr stores the resolved attribute in fields under the given groups.
+func (h *SlogHandler) addAttr(fields map[string]interface{}, groups []string, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+
+	if attr.Value.Kind() == slog.KindGroup {
+		members := attr.Value.Group()
+		if len(members) == 0 {
+			return
+		}
+
+		nested := groups
+		if attr.Key != "" {
+			nested = append(append(make([]string, 0, len(groups)+1), groups...), attr.Key)
+		}
+		for _, member := range members {
+			h.addAttr(fields, nested, member)
+		}
+
+		return
+	}
+
+	if !h.opts.NestGroups {
+		key := attr.Key
+		if len(groups) > 0 {
+			key = strings.Join(groups, ".") + "." + attr.Key
+		}
+		fields[key] = slogValue(attr.Value)
+
+		return
+	}
+
+	target := fields
+	for _, group := range groups {
+		child, ok := target[group].(map[string]interface{})
+		if !ok {
+			child = map[string]interface{}{}
+			target[group] = child
+		}
+		target = child
+	}
+	target[attr.Key] = slogValue(attr.Value)
+}
+
+// slogValue converts a resolved non-group slog.Value into a plain Go value, keeping errors and other types intact.
+func slogValue(value slog.Value) interface{} {
+	switch value.Kind() {
+	case slog.KindString:
+		return value.String()
+	case slog.KindInt64:
+		return value.Int64()
+	case slog.KindUint64:
+		return value.Uint64()
+	case slog.KindFloat64:
+		return value.Float64()
+	case slog.KindBool:
+		return value.Bool()
+	case slog.KindDuration:
+		return value.Duration()
+	case slog.KindTime:
+		return value.Time()
+	default:
+		return value.Any()
+	}
+}
@@ -0,0 +1,96 @@
+package composite_logger
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSlogTestLogger(t *testing.T, opts *SlogHandlerOptions) (*slog.Logger, *CompositeLogger, *entryOnlyLogger) {
+	t.Helper()
+
+	l := &entryOnlyLogger{}
+	cl, err := New(testSetting{l})
+	require.NoError(t, err)
+
+	return slog.New(NewSlogHandler(cl, opts)), cl, l
+}
+
+func TestSlogHandler_MapsRecordToEntry(t *testing.T) {
+	logger, cl, l := newSlogTestLogger(t, nil)
+	boom := errors.New("boom")
+
+	logger.Warn("slow query", "duration", 2*time.Second, "rows", 10, "err", boom)
+	cl.Stop()
+
+	require.Len(t, l.entries, 1)
+	entry := l.entries[0]
+	assert.Equal(t, WarningLevel, entry.Level)
+	assert.Equal(t, "slow query", entry.Message)
+	assert.Equal(t, 2*time.Second, entry.Fields["duration"])
+	assert.Equal(t, int64(10), entry.Fields["rows"])
+	assert.Same(t, boom, entry.Fields["err"])
+	assert.False(t, entry.Time.IsZero())
+	assert.Contains(t, entry.Caller.File, "slog_handler_test.go")
+}
+
+func TestSlogHandler_FlattensGroups(t *testing.T) {
+	logger, cl, l := newSlogTestLogger(t, nil)
+
+	logger.With("service", "api").WithGroup("http").With("method", "GET").
+		Info("served", "status", 200, slog.Group("client", "ip", "10.0.0.1"))
+	cl.Stop()
+
+	require.Len(t, l.entries, 1)
+	assert.Equal(t, map[string]interface{}{
+		"service":        "api",
+		"http.method":    "GET",
+		"http.status":    int64(200),
+		"http.client.ip": "10.0.0.1",
+	}, l.entries[0].Fields)
+}
+
+func TestSlogHandler_NestsGroups(t *testing.T) {
+	logger, cl, l := newSlogTestLogger(t, &SlogHandlerOptions{NestGroups: true})
+
+	logger.WithGroup("http").With("method", "GET").Info("served", "status", 200, slog.Group("client", "ip", "10.0.0.1"))
+	cl.Stop()
+
+	require.Len(t, l.entries, 1)
+	assert.Equal(t, map[string]interface{}{
+		"http": map[string]interface{}{
+			"method": "GET",
+			"status": int64(200),
+			"client": map[string]interface{}{"ip": "10.0.0.1"},
+		},
+	}, l.entries[0].Fields)
+}
+
+func TestSlogHandler_Enabled(t *testing.T) {
+	h := NewSlogHandler(nil, nil)
+	assert.False(t, h.Enabled(context.Background(), slog.LevelDebug))
+	assert.True(t, h.Enabled(context.Background(), slog.LevelInfo))
+
+	h = NewSlogHandler(nil, &SlogHandlerOptions{Level: TraceLevel})
+	assert.True(t, h.Enabled(context.Background(), slog.LevelDebug-4))
+}
+
+func TestSlogHandler_GlobalLoggerAndContextFields(t *testing.T) {
+	l := &fakeLogger{}
+	Init(testSetting{l})
+
+	logger := slog.New(NewSlogHandler(nil, nil))
+	logger.InfoContext(WithRequestID(context.Background(), "req-1"), "handled", slog.Group("empty"))
+	logger.Error("failed")
+	Stop()
+
+	require.Len(t, l.infoCalls, 1)
+	assert.Equal(t, map[string]interface{}{RequestIDKey: "req-1"}, l.infoCalls[0].context)
+	require.Len(t, l.errorCalls, 1)
+	assert.Contains(t, l.errorCalls[0].context, "stackTrace")
+}
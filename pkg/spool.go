@@ -0,0 +1,522 @@
+package composite_logger
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultSpoolMaxBytes bounds the size of a spool directory when no limit is configured.
+	defaultSpoolMaxBytes = 64 << 20
+	// defaultSpoolMaxAge bounds the age of spooled entries when no limit is configured.
+	defaultSpoolMaxAge = 24 * time.Hour
+	// spoolSegmentSize is the size after which a new segment file is started.
+	spoolSegmentSize = 1 << 20
+)
+
+// Spool makes the delivery to one adapter durable. Every entry for the adapter is appended to segment files
+// in Dir before it is queued for the adapter and acknowledged once the adapter has handled it; entries that were
+// not acknowledged, because the process stopped or the adapter failed, are delivered again by the next logger
+// using the same Dir. Delivery is at least once: an entry may be repeated if the process stops between delivery
+// and acknowledgement.
+//
+// Entries are written by a goroutine of the adapter, so a slow disk never holds up the other adapters.
+// They wait for it in an intake queue sized and governed like the adapter queue, see AdapterSetting.QueueSize;
+// entries still waiting there are lost if the process crashes. Entries the adapter queue drops are already
+// on disk and delivered by the next logger.
+//
+// Entries that were sampled out, filtered or held back as repeats count as handled. Fields are stored as JSON,
+// so replayed entries carry JSON values, e.g. float64 numbers and error messages instead of errors.
+// When a limit is exceeded the oldest segments are removed and their entries counted as dropped.
+//
+// Usage:
+//
+//	composite_logger.AdapterSetting{
+//		Setting: setting.TelegramSetting{Enabled: true, BotKey: "KEY", ChatId: 1},
+//		Spool:   &composite_logger.Spool{Dir: "/var/spool/myapp/telegram"},
+//	}
+type Spool struct {
+	// Dir is the directory holding the segment files. It is created if it does not exist
+	// and must not be shared with other adapters; loggers of one process cannot open it at the same time.
+	Dir string
+	// MaxBytes bounds the total size of the segment files (default: 64 MiB).
+	MaxBytes int64
+	// MaxAge is how long unacknowledged entries are kept (default: 24 hours).
+	MaxAge time.Duration
+	// Sync makes every append wait until the entry is written to stable storage. It protects against
+	// power loss rather than process crashes, at the cost of one fsync per entry.
+	Sync bool
+}
+
+// spoolRecord is the stored form of an entry.
+type spoolRecord struct {
+	ID       uint64                 `json:"id"`
+	Level    Level                  `json:"level"`
+	Time     time.Time              `json:"time"`
+	Message  string                 `json:"message"`
+	Fields   map[string]interface{} `json:"fields,omitempty"`
+	Logger   string                 `json:"logger,omitempty"`
+	Caller   Caller                 `json:"caller"`
+	Stack    string                 `json:"stack,omitempty"`
+	Sequence uint64                 `json:"sequence"`
+}
+
+// segment is a pair of files: the entries, one JSON record per line, and the IDs of acknowledged entries.
+type segment struct {
+	first    uint64
+	modified time.Time
+	size     int64
+	pending  map[uint64]struct{}
+	log      *os.File
+	acks     *os.File
+}
+
+// spoolDirs holds the spool directories open in this process, so two loggers never own the same one:
+// a spool treats segments it did not write as finished and removes them once they are acknowledged.
+var (
+	spoolDirsMu sync.Mutex
+	spoolDirs   = make(map[string]bool)
+)
+
+// lockSpoolDir reserves dir for one spool and returns its cleaned absolute path.
+func lockSpoolDir(dir string) (string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+
+	spoolDirsMu.Lock()
+	defer spoolDirsMu.Unlock()
+	if spoolDirs[abs] {
+		return "", fmt.Errorf("%w: %s", ErrSpoolInUse, dir)
+	}
+	spoolDirs[abs] = true
+
+	return abs, nil
+}
+
+// unlockSpoolDir releases a directory reserved by lockSpoolDir.
+func unlockSpoolDir(abs string) {
+	spoolDirsMu.Lock()
+	defer spoolDirsMu.Unlock()
+	delete(spoolDirs, abs)
+}
+
+// spool implements Spool. Entries are appended by the adapter's spool goroutine and acknowledged by its worker.
+type spool struct {
+	dir      string
+	// lock is the absolute path reserved with lockSpoolDir until the spool is closed.
+	lock     string
+	maxBytes int64
+	maxAge   time.Duration
+	sync     bool
+
+	mu       sync.Mutex
+	segments []*segment
+	nextID   uint64
+	closed   bool
+}
+
+// openSpool opens the spool directory and returns the entries that still have to be delivered, oldest first,
+// together with the number of entries discarded because they exceeded the limits.
+// It fails with ErrSpoolInUse while another spool of the process has the directory open.
+func openSpool(cfg *Spool) (*spool, []Entry, uint64, error) {
+	lock, err := lockSpoolDir(cfg.Dir)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	s, backlog, dropped, err := loadSpool(cfg)
+	if err != nil {
+		unlockSpoolDir(lock)
+		return nil, nil, 0, err
+	}
+	s.lock = lock
+
+	return s, backlog, dropped, nil
+}
+
+// loadSpool reads the segments of the spool directory.
+func loadSpool(cfg *Spool) (*spool, []Entry, uint64, error) {
+	s := &spool{dir: cfg.Dir, maxBytes: cfg.MaxBytes, maxAge: cfg.MaxAge, sync: cfg.Sync, nextID: 1}
+	if s.maxBytes <= 0 {
+		s.maxBytes = defaultSpoolMaxBytes
+	}
+	if s.maxAge <= 0 {
+		s.maxAge = defaultSpoolMaxAge
+	}
+
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return nil, nil, 0, err
+	}
+
+	paths, err := filepath.Glob(filepath.Join(s.dir, "*.log"))
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	sort.Strings(paths)
+
+	var backlog []Entry
+	for _, path := range paths {
+		seg, entries, err := s.load(path)
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		if seg == nil {
+			continue
+		}
+
+		s.segments = append(s.segments, seg)
+		backlog = append(backlog, entries...)
+	}
+
+	dropped, backlog := s.enforceLimits(time.Now(), backlog)
+
+	return s, backlog, dropped, nil
+}
+
+// load reads a segment and returns it with its unacknowledged entries. Fully acknowledged segments are removed.
+// A truncated last line, left by a crash in the middle of a write, is ignored.
+func (s *spool) load(path string) (*segment, []Entry, error) {
+	first, err := strconv.ParseUint(strings.TrimSuffix(filepath.Base(path), ".log"), 10, 64)
+	if err != nil {
+		return nil, nil, nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	acked, err := readAcks(ackPath(path))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+
+	seg := &segment{first: first, modified: info.ModTime(), size: info.Size(), pending: make(map[uint64]struct{})}
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64<<10), spoolSegmentSize*4)
+	for scanner.Scan() {
+		var record spoolRecord
+		if json.Unmarshal(scanner.Bytes(), &record) != nil {
+			continue
+		}
+		if record.ID >= s.nextID {
+			s.nextID = record.ID + 1
+		}
+		if _, ok := acked[record.ID]; ok {
+			continue
+		}
+
+		seg.pending[record.ID] = struct{}{}
+		entries = append(entries, record.entry())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	if len(seg.pending) == 0 {
+		return nil, nil, removeSegment(path)
+	}
+
+	return seg, entries, nil
+}
+
+func readAcks(path string) (map[uint64]struct{}, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	acked := make(map[uint64]struct{})
+	for _, line := range strings.Split(string(data), "\n") {
+		if id, err := strconv.ParseUint(line, 10, 64); err == nil {
+			acked[id] = struct{}{}
+		}
+	}
+
+	return acked, nil
+}
+
+// append stores the entry and returns its ID, together with the number of older entries discarded
+// to stay within the limits.
+func (s *spool) append(entry Entry) (uint64, uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return 0, 0, nil
+	}
+
+	id := s.nextID
+	line, err := json.Marshal(newSpoolRecord(id, entry))
+	if err != nil {
+		return 0, 0, err
+	}
+	line = append(line, '\n')
+
+	seg, err := s.current(id)
+	if err != nil {
+		return 0, 0, err
+	}
+	if _, err := seg.log.Write(line); err != nil {
+		return 0, 0, err
+	}
+	if s.sync {
+		if err := seg.log.Sync(); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	s.nextID++
+	seg.size += int64(len(line))
+	seg.modified = time.Now()
+	seg.pending[id] = struct{}{}
+
+	dropped, _ := s.enforceLimits(seg.modified, nil)
+	return id, dropped, nil
+}
+
+// current returns the segment new entries are appended to, starting a new one if the last is full
+// or was written by a previous process.
+func (s *spool) current(id uint64) (*segment, error) {
+	if n := len(s.segments); n > 0 {
+		last := s.segments[n-1]
+		if last.log != nil && last.size < spoolSegmentSize {
+			return last, nil
+		}
+		if last.log != nil {
+			_ = last.log.Close()
+			last.log = nil
+		}
+		if len(last.pending) == 0 {
+			s.remove(n - 1)
+		}
+	}
+
+	file, err := os.OpenFile(s.path(id), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	seg := &segment{first: id, modified: time.Now(), pending: make(map[uint64]struct{}), log: file}
+	s.segments = append(s.segments, seg)
+
+	return seg, nil
+}
+
+// ack records that the entry with the given ID has been handled.
+// Segments whose entries have all been handled are removed, except the one still being written.
+func (s *spool) ack(id uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.find(id)
+	if i < 0 || s.closed {
+		return nil
+	}
+
+	seg := s.segments[i]
+	if _, ok := seg.pending[id]; !ok {
+		return nil
+	}
+	delete(seg.pending, id)
+
+	if len(seg.pending) == 0 && seg.log == nil {
+		s.remove(i)
+		return nil
+	}
+
+	if seg.acks == nil {
+		file, err := os.OpenFile(ackPath(s.path(seg.first)), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return err
+		}
+		seg.acks = file
+	}
+
+	_, err := seg.acks.Write(strconv.AppendUint(nil, id, 10))
+	if err == nil {
+		_, err = seg.acks.Write([]byte{'\n'})
+	}
+
+	return err
+}
+
+// enforceLimits removes the oldest segments while the spool exceeds its size limit or they exceed the age limit.
+// The segment being written is never removed. It returns the number of discarded entries and the backlog
+// without them.
+func (s *spool) enforceLimits(now time.Time, backlog []Entry) (uint64, []Entry) {
+	var size int64
+	for _, seg := range s.segments {
+		size += seg.size
+	}
+
+	var dropped uint64
+	for len(s.segments) > 0 && s.segments[0].log == nil {
+		oldest := s.segments[0]
+		if size <= s.maxBytes && now.Sub(oldest.modified) <= s.maxAge {
+			break
+		}
+
+		size -= oldest.size
+		dropped += uint64(len(oldest.pending))
+		s.remove(0)
+	}
+
+	if dropped > 0 && len(backlog) > 0 {
+		kept := backlog[:0]
+		for _, entry := range backlog {
+			if s.find(entry.spoolID) >= 0 {
+				kept = append(kept, entry)
+			}
+		}
+		backlog = kept
+	}
+
+	return dropped, backlog
+}
+
+// find returns the index of the segment holding the entry with the given ID, or -1.
+func (s *spool) find(id uint64) int {
+	for i := len(s.segments) - 1; i >= 0; i-- {
+		if s.segments[i].first <= id {
+			if _, ok := s.segments[i].pending[id]; ok {
+				return i
+			}
+			return -1
+		}
+	}
+
+	return -1
+}
+
+// remove closes and deletes the files of the i-th segment.
+func (s *spool) remove(i int) {
+	seg := s.segments[i]
+	if seg.log != nil {
+		_ = seg.log.Close()
+	}
+	if seg.acks != nil {
+		_ = seg.acks.Close()
+	}
+	_ = removeSegment(s.path(seg.first))
+
+	s.segments = append(s.segments[:i], s.segments[i+1:]...)
+}
+
+// close closes the open files and releases the directory. Unacknowledged entries stay on disk for the next logger.
+func (s *spool) close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.closed && s.lock != "" {
+		defer unlockSpoolDir(s.lock)
+	}
+	s.closed = true
+	var errs []error
+	for _, seg := range s.segments {
+		if seg.log != nil {
+			errs = append(errs, seg.log.Close())
+			seg.log = nil
+		}
+		if seg.acks != nil {
+			errs = append(errs, seg.acks.Close())
+			seg.acks = nil
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *spool) path(first uint64) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%020d.log", first))
+}
+
+func ackPath(logPath string) string {
+	return strings.TrimSuffix(logPath, ".log") + ".ack"
+}
+
+func removeSegment(path string) error {
+	err := os.Remove(path)
+	if ackErr := os.Remove(ackPath(path)); ackErr != nil && !errors.Is(ackErr, os.ErrNotExist) {
+		err = errors.Join(err, ackErr)
+	}
+
+	return err
+}
+
+func newSpoolRecord(id uint64, entry Entry) spoolRecord {
+	entry = entry.flatten()
+
+	return spoolRecord{
+		ID:       id,
+		Level:    entry.Level,
+		Time:     entry.Time,
+		Message:  entry.Message,
+		Fields:   jsonFields(entry.Fields),
+		Logger:   entry.Logger,
+		Caller:   entry.Caller,
+		Stack:    entry.Stack,
+		Sequence: entry.Sequence,
+	}
+}
+
+func (r spoolRecord) entry() Entry {
+	return Entry{
+		Level:    r.Level,
+		Time:     r.Time,
+		Message:  r.Message,
+		Fields:   r.Fields,
+		Logger:   r.Logger,
+		Caller:   r.Caller,
+		Stack:    r.Stack,
+		Sequence: r.Sequence,
+		spoolID:  r.ID,
+	}
+}
+
+// jsonFields converts field values that cannot be stored as JSON: errors become their messages,
+// other values that fail to encode are formatted with fmt.
+func jsonFields(fields map[string]interface{}) map[string]interface{} {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	converted := make(map[string]interface{}, len(fields))
+	for key, value := range fields {
+		switch typed := value.(type) {
+		case error:
+			converted[key] = typed.Error()
+		case map[string]interface{}:
+			converted[key] = jsonFields(typed)
+		default:
+			if _, err := json.Marshal(value); err != nil {
+				converted[key] = fmt.Sprint(value)
+			} else {
+				converted[key] = value
+			}
+		}
+	}
+
+	return converted
+}
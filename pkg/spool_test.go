@@ -0,0 +1,204 @@
+package composite_logger
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpool_ReplaysUndeliveredEntriesAfterRestart(t *testing.T) {
+	dir := t.TempDir()
+	down := &checkedLogger{err: errors.New("telegram unreachable")}
+
+	cl, err := NewWithOptions(Options{ErrorHandler: func(*AdapterError) {}},
+		AdapterSetting{Setting: testSetting{down}, Name: "telegram", Spool: &Spool{Dir: dir}})
+	require.NoError(t, err)
+	cl.Error("disk almost full", map[string]interface{}{"free": 3, "error": errors.New("ENOSPC")})
+	cl.Info("backup finished", nil)
+	cl.Stop()
+
+	up := &fakeLogger{}
+	cl, err = New(AdapterSetting{Setting: testSetting{up}, Name: "telegram", Spool: &Spool{Dir: dir}})
+	require.NoError(t, err)
+	cl.Info("after restart", nil)
+	cl.Stop()
+
+	require.Len(t, up.errorCalls, 1)
+	assert.Equal(t, "disk almost full", up.errorCalls[0].message)
+	assert.Equal(t, float64(3), up.errorCalls[0].context["free"], "fields are restored as JSON values")
+	assert.Equal(t, "ENOSPC", up.errorCalls[0].context["error"])
+	require.Len(t, up.infoCalls, 2)
+	assert.Equal(t, "backup finished", up.infoCalls[0].message, "replayed entries come first")
+	assert.Equal(t, "after restart", up.infoCalls[1].message)
+
+	again := &fakeLogger{}
+	cl, err = New(AdapterSetting{Setting: testSetting{again}, Name: "telegram", Spool: &Spool{Dir: dir}})
+	require.NoError(t, err)
+	cl.Stop()
+
+	assert.Empty(t, again.infoCalls, "acknowledged entries are not replayed")
+	assert.Empty(t, again.errorCalls)
+}
+
+func TestSpool_WritesOffTheDispatcher(t *testing.T) {
+	dir := t.TempDir()
+	s := newSink("telegram", &fakeLogger{}, AdapterSetting{Spool: &Spool{Dir: dir}}, Options{})
+	require.NoError(t, s.openSpool(&Spool{Dir: dir}))
+
+	s.offer(Entry{Level: InfoLevel, Message: "queued"})
+	files, _ := filepath.Glob(filepath.Join(dir, "*.log"))
+	assert.Empty(t, files, "offer only queues the entry")
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go s.runSpool(&wg)
+	close(s.intake.ch)
+	wg.Wait()
+
+	files, _ = filepath.Glob(filepath.Join(dir, "*.log"))
+	assert.Len(t, files, 1)
+	entry, ok := <-s.queue.ch
+	require.True(t, ok)
+	assert.NotZero(t, entry.spoolID, "entries reach the adapter queue once they are persisted")
+	require.NoError(t, s.spool.close())
+}
+
+func TestSpool_FlushWaitsForSpooledEntries(t *testing.T) {
+	up := &fakeLogger{}
+	cl, err := New(AdapterSetting{Setting: testSetting{up}, Name: "telegram", Spool: &Spool{Dir: t.TempDir()}})
+	require.NoError(t, err)
+	defer cl.Stop()
+
+	cl.Info("first", nil)
+	cl.Info("second", nil)
+	require.NoError(t, cl.Flush(context.Background()))
+
+	assert.Len(t, up.infoCalls, 2)
+}
+
+func TestSpool_ReInitHandsOverTheDirectory(t *testing.T) {
+	dir := t.TempDir()
+	down := &checkedLogger{err: errors.New("telegram unreachable")}
+	InitWithOptions(Options{ErrorHandler: func(*AdapterError) {}},
+		AdapterSetting{Setting: testSetting{down}, Name: "telegram", Spool: &Spool{Dir: dir}})
+	Info("before re-init", nil)
+
+	_, err := New(AdapterSetting{Setting: testSetting{&fakeLogger{}}, Spool: &Spool{Dir: dir}})
+	assert.ErrorIs(t, err, ErrSpoolInUse, "two loggers never own the same directory")
+
+	up := &fakeLogger{}
+	Init(AdapterSetting{Setting: testSetting{up}, Name: "telegram", Spool: &Spool{Dir: dir}})
+	Info("after re-init", nil)
+	Stop()
+
+	require.Len(t, up.infoCalls, 2)
+	assert.Equal(t, "before re-init", up.infoCalls[0].message, "entries of the previous logger are replayed")
+	assert.Equal(t, "after re-init", up.infoCalls[1].message)
+
+	again := &fakeLogger{}
+	cl, err := New(AdapterSetting{Setting: testSetting{again}, Name: "telegram", Spool: &Spool{Dir: dir}})
+	require.NoError(t, err)
+	cl.Stop()
+	assert.Empty(t, again.infoCalls)
+}
+
+func TestSpool_FailedNewReleasesTheDirectory(t *testing.T) {
+	dir := t.TempDir()
+	_, err := NewWithOptions(Options{Routes: []Route{{Adapters: []string{"missing"}}}},
+		AdapterSetting{Setting: testSetting{&fakeLogger{}}, Spool: &Spool{Dir: dir}})
+	require.Error(t, err)
+
+	cl, err := New(AdapterSetting{Setting: testSetting{&fakeLogger{}}, Spool: &Spool{Dir: dir}})
+	require.NoError(t, err)
+	cl.Stop()
+}
+
+func TestSpool_AcknowledgesAndRemovesSegments(t *testing.T) {
+	dir := t.TempDir()
+	s, backlog, _, err := openSpool(&Spool{Dir: dir})
+	require.NoError(t, err)
+	assert.Empty(t, backlog)
+
+	first, _, err := s.append(Entry{Level: InfoLevel, Message: "first"})
+	require.NoError(t, err)
+	second, _, err := s.append(Entry{Level: InfoLevel, Message: "second"})
+	require.NoError(t, err)
+	require.NoError(t, s.ack(first))
+	require.NoError(t, s.close())
+
+	s, backlog, _, err = openSpool(&Spool{Dir: dir})
+	require.NoError(t, err)
+	require.Len(t, backlog, 1)
+	assert.Equal(t, "second", backlog[0].Message)
+	assert.Equal(t, second, backlog[0].spoolID)
+
+	third, _, err := s.append(Entry{Level: InfoLevel, Message: "third"})
+	require.NoError(t, err)
+	assert.Greater(t, third, second, "IDs keep growing across restarts")
+
+	require.NoError(t, s.ack(second))
+	files, _ := filepath.Glob(filepath.Join(dir, "*.log"))
+	assert.Len(t, files, 1, "the fully acknowledged segment is removed")
+	require.NoError(t, s.close())
+}
+
+func TestSpool_EnforcesLimitsAndSkipsTruncatedLines(t *testing.T) {
+	dir := t.TempDir()
+	s, _, _, err := openSpool(&Spool{Dir: dir})
+	require.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		_, _, err = s.append(Entry{Level: InfoLevel, Message: "queued"})
+		require.NoError(t, err)
+	}
+	require.NoError(t, s.close())
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.log"))
+	require.Len(t, files, 1)
+	file, err := os.OpenFile(files[0], os.O_WRONLY|os.O_APPEND, 0o644)
+	require.NoError(t, err)
+	_, _ = file.WriteString(`{"id":99,"mess`)
+	require.NoError(t, file.Close())
+
+	s, backlog, dropped, err := openSpool(&Spool{Dir: dir})
+	require.NoError(t, err)
+	assert.Len(t, backlog, 3)
+	assert.Zero(t, dropped)
+	require.NoError(t, s.close())
+
+	old := time.Now().Add(-2 * time.Hour)
+	require.NoError(t, os.Chtimes(files[0], old, old))
+	_, backlog, dropped, err = openSpool(&Spool{Dir: dir, MaxAge: time.Hour})
+	require.NoError(t, err)
+	assert.Empty(t, backlog)
+	assert.Equal(t, uint64(3), dropped)
+}
+
+func TestSpool_SizeLimitCountsDroppedEntries(t *testing.T) {
+	dir := t.TempDir()
+	cl, err := NewWithOptions(Options{ErrorHandler: func(*AdapterError) {}, DropReportInterval: -1},
+		AdapterSetting{Setting: testSetting{&checkedLogger{err: errors.New("down")}}, Spool: &Spool{Dir: dir}})
+	require.NoError(t, err)
+	cl.Info("lost", nil)
+	cl.Stop()
+
+	cl, err = New(AdapterSetting{Setting: testSetting{&fakeLogger{}}, Spool: &Spool{Dir: dir, MaxBytes: 1}})
+	require.NoError(t, err)
+	defer cl.Stop()
+
+	assert.Equal(t, uint64(1), cl.Dropped())
+}
+
+func TestSpool_InvalidDirectory(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "file")
+	require.NoError(t, os.WriteFile(file, nil, 0o644))
+
+	_, err := New(AdapterSetting{Setting: testSetting{&fakeLogger{}}, Name: "telegram", Spool: &Spool{Dir: file}})
+	assert.ErrorContains(t, err, `composite_logger: spool of adapter "telegram"`)
+}